@@ -1,9 +1,11 @@
 package botan
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/isvinogradov/botan/entities"
@@ -11,6 +13,7 @@ import (
 
 const (
 	defaultPostJsonTimeoutSeconds        = 5
+	defaultUploadTimeoutSeconds          = 300
 	defaultLongPollTimeoutSeconds        = 300
 	defaultGetUpdatesFailCooldownSeconds = 10
 )
@@ -20,6 +23,25 @@ type Bot struct {
 	urls        *BotUrlContainer // todo make unexported?
 	callbacks   *BotCallbacksContainer
 	requestGate *requestGate
+	handler     Handler // routes updates to callbacks, wrapped with middleware
+	log         *botLogger
+	ctx         context.Context // context for all API requests made through this Bot; see WithContext
+}
+
+// WithContext returns a shallow copy of bot which makes all API requests with ctx: cancelling ctx aborts
+// the request in flight and pending retries. The copy shares configuration, callbacks and connections with bot.
+func (bot *Bot) WithContext(ctx context.Context) *Bot {
+	botCopy := *bot
+	botCopy.ctx = ctx
+	return &botCopy
+}
+
+// context for API requests; background context if none was set with WithContext
+func (bot *Bot) context() context.Context {
+	if bot.ctx != nil {
+		return bot.ctx
+	}
+	return context.Background()
 }
 
 func NewBot(conf *Config, callbacks *BotCallbacksContainer) (*Bot, error) {
@@ -31,20 +53,80 @@ func NewBot(conf *Config, callbacks *BotCallbacksContainer) (*Bot, error) {
 	if conf.Token == "" {
 		return nil, errors.New("bot token not specified in config")
 	}
+	if strings.Contains(conf.WebhookSecretPath, "/") {
+		return nil, errors.New("webhook secret path must be a single path segment")
+	}
+	if conf.APIBaseURL == "" {
+		conf.APIBaseURL = TelegramApiHost
+	}
+	conf.APIBaseURL = strings.TrimSuffix(conf.APIBaseURL, "/")
+	if conf.FileBaseURL == "" {
+		conf.FileBaseURL = conf.APIBaseURL + "/file"
+	}
+	conf.FileBaseURL = strings.TrimSuffix(conf.FileBaseURL, "/")
+	if conf.ProxyURL == "" {
+		conf.ProxyURL = conf.Socks5ConnectionString
+	}
 	if conf.PostJsonTimeoutSeconds < 1 {
 		conf.PostJsonTimeoutSeconds = defaultPostJsonTimeoutSeconds
 	}
+	if conf.UploadTimeoutSeconds < 1 {
+		conf.UploadTimeoutSeconds = defaultUploadTimeoutSeconds
+	}
 	if conf.LongPollTimeoutSeconds < 1 {
 		conf.LongPollTimeoutSeconds = defaultLongPollTimeoutSeconds
 	}
 	if conf.GetUpdatesFailCooldownSeconds < 1 {
 		conf.GetUpdatesFailCooldownSeconds = defaultGetUpdatesFailCooldownSeconds
 	}
+	if conf.Workers < 1 {
+		conf.Workers = defaultWorkers
+	}
+	if conf.PanicRetries < 1 {
+		conf.PanicRetries = defaultPanicRetries
+	}
+	if conf.RetryBaseDelayMilliseconds < 1 {
+		conf.RetryBaseDelayMilliseconds = defaultRetryBaseDelayMilliseconds
+	}
+	if conf.RetryMaxDelaySeconds < 1 {
+		conf.RetryMaxDelaySeconds = defaultRetryMaxDelaySeconds
+	}
+	if conf.RateLimiter == nil {
+		conf.RateLimiter = NewMemoryRateLimiter()
+	}
+	if conf.GlobalRateLimit.Requests < 1 {
+		conf.GlobalRateLimit = defaultGlobalRateLimit
+	}
+	if conf.PrivateChatRateLimit.Requests < 1 {
+		conf.PrivateChatRateLimit = defaultPrivateChatRateLimit
+	}
+	if conf.GroupChatRateLimit.Requests < 1 {
+		conf.GroupChatRateLimit = defaultGroupChatRateLimit
+	}
 
+	log := newBotLogger(conf.Logger, conf.Token)
 	requestGate := requestGate{
-		postTimeoutSeconds:     conf.PostJsonTimeoutSeconds,
-		getTimeoutSeconds:      conf.LongPollTimeoutSeconds + 1,
-		socks5ConnectionString: conf.Socks5ConnectionString,
+		log:                  log,
+		postTimeoutSeconds:   conf.PostJsonTimeoutSeconds,
+		uploadTimeoutSeconds: conf.UploadTimeoutSeconds,
+		getTimeoutSeconds:    conf.LongPollTimeoutSeconds + 1,
+		proxyUrl:             conf.ProxyURL,
+		proxyFromEnvironment: conf.ProxyFromEnvironment,
+		apiBaseUrl:           conf.APIBaseURL,
+		httpClient:           conf.HTTPClient,
+		retry: retryPolicy{
+			maxRetries: conf.MaxRetries,
+			baseDelay:  time.Duration(conf.RetryBaseDelayMilliseconds) * time.Millisecond,
+			maxDelay:   time.Duration(conf.RetryMaxDelaySeconds) * time.Second,
+		},
+		limiter: outgoingLimiter{
+			botKey:      rateLimitBotKey(conf.Token),
+			policy:      conf.RateLimitPolicy,
+			limiter:     conf.RateLimiter,
+			global:      conf.GlobalRateLimit,
+			privateChat: conf.PrivateChatRateLimit,
+			groupChat:   conf.GroupChatRateLimit,
+		},
 	}
 	if errRG := requestGate.checkAndInit(); errRG != nil {
 		return nil, errRG
@@ -55,17 +137,18 @@ func NewBot(conf *Config, callbacks *BotCallbacksContainer) (*Bot, error) {
 		return nil, errors.New("nil callback container pointer")
 	}
 
-	bot := Bot{config: conf, callbacks: callbacks, requestGate: &requestGate}
-	bot.callbacks.checkAndInit()
+	bot := Bot{config: conf, callbacks: callbacks, requestGate: &requestGate, log: log}
+	bot.callbacks.checkAndInit(log)
+	bot.handler = chainMiddleware(bot.callbacks.Middleware, (*Bot).routeUpdate)
 	generateUrlsForBot(&bot)
 	rand.Seed(time.Now().Unix()) // rand seed for GetRandomQuestion
 	return &bot, nil
 }
 
 // Get bulk of updates for bot
-func (bot *Bot) fetchGetUpdatesResponse(url string) (*entities.GetUpdatesResponse, error) {
+func (bot *Bot) fetchGetUpdatesResponse(ctx context.Context, url string) (*entities.GetUpdatesResponse, error) {
 	var uResp entities.GetUpdatesResponse
-	if parseError := bot.requestGate.makeGetRequest(url, &uResp); parseError != nil {
+	if parseError := bot.requestGate.makeGetRequest(ctx, url, &uResp); parseError != nil {
 		return nil, parseError
 	}
 	if !uResp.OK {
@@ -74,15 +157,23 @@ func (bot *Bot) fetchGetUpdatesResponse(url string) (*entities.GetUpdatesRespons
 	return &uResp, nil
 }
 
-// GetUpdates long polling loop
+// GetUpdates long polling loop; never returns. Use Run to be able to stop the loop.
 func (bot *Bot) GetUpdates() {
-	fmt.Println("started getUpdates loop")
+	_ = bot.Run(context.Background())
+}
 
+// Run starts long polling loop and blocks until ctx is cancelled. Pending getUpdates request is aborted on
+// cancellation; Run waits for the already scheduled updates to be processed, stores the final offset
+// via OnSetNewOffset and confirms it to Telegram with one last getUpdates call before returning. Updates fetched but not processed will be received again on the next start.
+// Returned error wraps ctx.Err(), so errors.Is(err, context.Canceled) can be used to check for graceful shutdown.
+func (bot *Bot) Run(ctx context.Context) error {
 	// get updateID (offset) from last run; if this is a first ever call to Redis, offset == 0
 	offset := bot.callbacks.OnGetOffset()
+	bot.log.info("started getUpdates loop", "offset", offset)
+	disp := newDispatcher(bot, bot.config.Workers, offset)
 
 	var url string
-	for {
+	for ctx.Err() == nil {
 		if offset > 0 {
 			// get first update after current offset
 			url = fmt.Sprintf("%s&offset=%d", bot.urls.getUpdates, offset+1)
@@ -92,52 +183,102 @@ func (bot *Bot) GetUpdates() {
 		}
 
 		// fetch response
-		response, updRespErr := bot.fetchGetUpdatesResponse(url)
+		response, updRespErr := bot.fetchGetUpdatesResponse(ctx, url)
 		if updRespErr != nil {
-			fmt.Println("got error in getUpdates; scheduling GetUpdates timeout...")
-			fmt.Println(updRespErr)
-			time.Sleep(time.Duration(bot.config.GetUpdatesFailCooldownSeconds) * time.Second)
-		} else {
-			for _, update := range response.Updates {
-				fmt.Printf("Processing update %d\n", update.UpdateId)
-				var cbErr error
-				// At most one of (message, edited_message, channel_post, edited_channel_post, inline_query,
-				// chosen_inline_result, callback_query, shipping_query, pre_checkout_query) can be present
-				// in any given update.
-
-				// According to BotCallbacksContainer configuration, in Update, we will never receive an entity
-				// with to respective callback set. So, for instance, if we received a CallbackQuery in Update,
-				// we can be sure that OnCallbackQuery callback != nil in BotCallbacksContainer.
-
-				if update.Message != nil {
-					cbErr = bot.callbacks.OnMessage(bot, update.Message)
-				} else if update.CallbackQuery != nil {
-					cbErr = bot.callbacks.OnCallbackQuery(bot, update.CallbackQuery)
-				} else if update.InlineQuery != nil {
-					cbErr = bot.callbacks.OnInlineQuery(bot, update.InlineQuery)
-				} else if update.EditedMessage != nil {
-					cbErr = bot.callbacks.OnEditedMessage(bot, update.EditedMessage)
-				} else if update.ChannelPost != nil {
-					cbErr = bot.callbacks.OnChannelPost(bot, update.ChannelPost)
-				} else if update.EditedChannelPost != nil {
-					cbErr = bot.callbacks.OnEditedChannelPost(bot, update.EditedChannelPost)
-				} else if update.ChosenInlineResult != nil {
-					cbErr = bot.callbacks.OnChosenInlineResult(bot, update.ChosenInlineResult)
-				} else if update.Poll != nil {
-					cbErr = bot.callbacks.OnPoll(bot, update.Poll)
-				}
-
-				// handle callback error
-				if cbErr != nil {
-					bot.callbacks.OnError(cbErr)
-				}
-
-				// everything OK
-				// set new offset, even if no callback is triggered
-				offset = update.UpdateId
-				// store new offset to external storage if provided
-				bot.callbacks.OnSetNewOffset(offset)
+			if ctx.Err() != nil {
+				break // request was aborted because of cancellation, not a failure
 			}
+			cooldown := time.Duration(bot.config.GetUpdatesFailCooldownSeconds) * time.Second
+			bot.log.error("getUpdates failed", append(errorFields(updRespErr), "cooldown", cooldown)...)
+			select {
+			case <-ctx.Done():
+			case <-time.After(cooldown):
+			}
+			continue
 		}
+
+		for i := range response.Updates {
+			update := &response.Updates[i]
+			bot.log.debug("processing update", "update_id", update.UpdateId)
+			if !disp.dispatch(ctx, update) {
+				break // the rest of updates will be redelivered after restart
+			}
+			// request updates after the last scheduled one; offset is committed by dispatcher
+			// only when the update is processed, even if no callback is triggered
+			offset = update.UpdateId
+		}
+	}
+
+	committed := disp.stop()
+	if committed > 0 {
+		bot.confirmOffset(committed)
+	}
+	bot.log.info("stopped getUpdates loop", "offset", committed)
+	return fmt.Errorf("getUpdates loop stopped at offset %d: %w", committed, ctx.Err())
+}
+
+// Confirms processed updates with a final non-blocking getUpdates call, so that Telegram doesn't deliver them
+// again on the next start. The loop context is already cancelled at this point, so a separate timeout is used.
+func (bot *Bot) confirmOffset(offset int) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(bot.config.PostJsonTimeoutSeconds)*time.Second)
+	defer cancel()
+	url := fmt.Sprintf("%s?offset=%d&limit=1&timeout=0", bot.urls.method(MethodGetUpdates), offset+1)
+	if _, errConfirm := bot.fetchGetUpdatesResponse(ctx, url); errConfirm != nil {
+		bot.log.warn("failed to confirm offset", append(errorFields(errConfirm), "offset", offset)...)
+	}
+}
+
+// Passes a single update through middleware to the respective callback. Used both by GetUpdates loop
+// and by webhook handler. Panics in handlers are recovered according to Config.PanicPolicy.
+func (bot *Bot) processUpdate(update *entities.Update) {
+	// handle callback error
+	if cbErr := bot.handleUpdate(update); cbErr != nil {
+		bot.reportError(update, cbErr)
+	}
+}
+
+// Routes a single update to the respective callback. Updates without a callback are skipped: with polling,
+// allowed_updates filters them out, but a webhook may be set up to receive more update types than the bot handles.
+func (bot *Bot) routeUpdate(update *entities.Update) error {
+	// At most one of (message, edited_message, channel_post, edited_channel_post, inline_query,
+	// chosen_inline_result, callback_query, shipping_query, pre_checkout_query) can be present
+	// in any given update.
+	cb := bot.callbacks
+	switch {
+	case update.Message != nil && cb.OnMessage != nil:
+		return cb.OnMessage(bot, update.Message)
+	case update.CallbackQuery != nil:
+		return bot.processCallbackQuery(update.CallbackQuery)
+	case update.InlineQuery != nil && cb.OnInlineQuery != nil:
+		return cb.OnInlineQuery(bot, update.InlineQuery)
+	case update.EditedMessage != nil && cb.OnEditedMessage != nil:
+		return cb.OnEditedMessage(bot, update.EditedMessage)
+	case update.ChannelPost != nil && cb.OnChannelPost != nil:
+		return cb.OnChannelPost(bot, update.ChannelPost)
+	case update.EditedChannelPost != nil && cb.OnEditedChannelPost != nil:
+		return cb.OnEditedChannelPost(bot, update.EditedChannelPost)
+	case update.ChosenInlineResult != nil && cb.OnChosenInlineResult != nil:
+		return cb.OnChosenInlineResult(bot, update.ChosenInlineResult)
+	case update.Poll != nil && cb.OnPoll != nil:
+		return cb.OnPoll(bot, update.Poll)
+	case update.ShippingQuery != nil && cb.OnShippingQuery != nil:
+		return cb.OnShippingQuery(bot, update.ShippingQuery)
+	case update.PreCheckoutQuery != nil && cb.OnPreCheckoutQuery != nil:
+		return cb.OnPreCheckoutQuery(bot, update.PreCheckoutQuery)
+	}
+	bot.log.debug("no callback set for update, skipping", "update_id", update.UpdateId)
+	return nil
+}
+
+// Routes game launches to OnCallbackGame if it's set, and other callback queries to OnCallbackQuery.
+// Either of callbacks may be missing when only one kind of callback queries is expected.
+func (bot *Bot) processCallbackQuery(cbq *entities.CallbackQuery) error {
+	if cbq.GameShortName != "" && bot.callbacks.OnCallbackGame != nil {
+		return bot.callbacks.OnCallbackGame(bot, cbq)
+	}
+	if bot.callbacks.OnCallbackQuery == nil {
+		bot.log.debug("no callback set for callback query, skipping", "callback_query_id", cbq.Id)
+		return nil
 	}
+	return bot.callbacks.OnCallbackQuery(bot, cbq)
 }
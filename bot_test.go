@@ -0,0 +1,96 @@
+package botan_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+	"github.com/isvinogradov/botan/entities"
+)
+
+func TestRunGracefulShutdown(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled []string
+	var offsets []int
+	bot := srv.Bot(t, &botan.BotCallbacksContainer{
+		OnMessage: func(bot *botan.Bot, msg *entities.Message) error {
+			started <- struct{}{}
+			<-release
+			mu.Lock()
+			handled = append(handled, msg.Text)
+			mu.Unlock()
+			return nil
+		},
+		OnSetNewOffset: func(offset int) {
+			mu.Lock()
+			offsets = append(offsets, offset)
+			mu.Unlock()
+		},
+	})
+
+	srv.QueueMessage(42, "one")
+	srv.QueueMessage(42, "two")
+	srv.QueueMessage(42, "three")
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- bot.Run(ctx) }()
+
+	// all three updates are scheduled and the loop is polling for more when ctx is cancelled
+	<-started
+	waitForPoll(t, srv, 4)
+	cancel()
+	close(release)
+
+	select {
+	case errRun := <-stopped:
+		if !errors.Is(errRun, context.Canceled) {
+			t.Errorf("Run returned %v", errRun)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return after cancellation")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 3 {
+		t.Errorf("handled %q, want all three updates", handled)
+	}
+	if len(offsets) == 0 || offsets[len(offsets)-1] != 3 {
+		t.Errorf("stored offsets %v, want last 3", offsets)
+	}
+	// long polls are sent with timeout, the final confirmation is sent without it
+	calls := srv.Calls(botan.MethodGetUpdates)
+	var confirmations int
+	for _, call := range calls {
+		if call.Int("timeout") == 0 {
+			confirmations++
+		}
+	}
+	if last := calls[len(calls)-1]; confirmations != 1 || last.Int("offset") != 4 || last.Int("timeout") != 0 {
+		t.Errorf("final getUpdates %v, %d confirmations; want one with offset 4 without timeout", last.Params, confirmations)
+	}
+}
+
+// Waits until getUpdates is called with the offset
+func waitForPoll(t *testing.T, srv *botantest.Server, offset int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, call := range srv.Calls(botan.MethodGetUpdates) {
+			if call.Int("offset") == offset {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("getUpdates with offset %d not called", offset)
+}
@@ -0,0 +1,183 @@
+package botantest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan"
+)
+
+// Method call received by the server
+type Call struct {
+	Method string                  // API method name, e.g. sendMessage
+	Params map[string]interface{}  // parameters decoded from JSON, form fields or query string
+	Files  map[string]UploadedFile // files uploaded with multipart/form-data, by field name
+	Time   time.Time               // when the call was received
+}
+
+// File uploaded with multipart/form-data
+type UploadedFile struct {
+	Name    string
+	Content []byte
+}
+
+// Returns parameter as a string: strings as is, other values JSON-serialized. Empty if not set.
+func (c Call) String(name string) string {
+	switch v := c.Params[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// Returns integer parameter; zero if it's not set or not a number
+func (c Call) Int(name string) int64 {
+	switch v := c.Params[name].(type) {
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+// Decodes parameters into target, e.g. into botan.SendMessageRequest
+func (c Call) Decode(target interface{}) error {
+	encoded, errMarshal := json.Marshal(c.Params)
+	if errMarshal != nil {
+		return errMarshal
+	}
+	return json.Unmarshal(encoded, target)
+}
+
+func (c Call) GoString() string {
+	encoded, _ := json.Marshal(c.Params)
+	return fmt.Sprintf("%s %s", c.Method, encoded)
+}
+
+// Returns recorded calls of the given methods in order they were received; all calls if no methods are given
+func (s *Server) Calls(methods ...string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []Call
+	for _, call := range s.calls {
+		if len(methods) == 0 || containsMethod(methods, call.Method) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Returns the last call of method
+func (s *Server) LastCall(method string) (Call, bool) {
+	calls := s.Calls(method)
+	if len(calls) == 0 {
+		return Call{}, false
+	}
+	return calls[len(calls)-1], true
+}
+
+// Forgets recorded calls
+func (s *Server) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Waits until method is called at least once more than it had been when WaitForCall was called, or fails the test
+// after timeout. Updates are processed asynchronously, so use it before asserting on calls made by handlers.
+func (s *Server) WaitForCall(tb testing.TB, method string, timeout time.Duration) Call {
+	tb.Helper()
+	expected := len(s.Calls(method)) + 1
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		s.mu.Lock()
+		recorded := s.callRecorded
+		s.mu.Unlock()
+		if calls := s.Calls(method); len(calls) >= expected {
+			return calls[expected-1]
+		}
+		select {
+		case <-recorded:
+		case <-deadline.C:
+			tb.Fatalf("botantest: %s was not called within %s", method, timeout)
+			return Call{}
+		}
+	}
+}
+
+// Fails the test if method was never called
+func (s *Server) AssertCalled(tb testing.TB, method string) {
+	tb.Helper()
+	if len(s.Calls(method)) == 0 {
+		tb.Errorf("botantest: expected %s to be called; calls: %s", method, s.describeCalls())
+	}
+}
+
+// Fails the test if method was called
+func (s *Server) AssertNotCalled(tb testing.TB, method string) {
+	tb.Helper()
+	if calls := s.Calls(method); len(calls) > 0 {
+		tb.Errorf("botantest: expected %s not to be called, got %d calls: %#v", method, len(calls), calls)
+	}
+}
+
+// Fails the test unless a message with exactly this text was sent to the chat
+func (s *Server) AssertSentMessage(tb testing.TB, chatId int64, text string) {
+	tb.Helper()
+	messages := s.Calls(botan.MethodSendMessage)
+	for _, call := range messages {
+		if call.Int("chat_id") == chatId && call.String("text") == text {
+			return
+		}
+	}
+	tb.Errorf("botantest: expected message %q to chat %d; sent messages: %s", text, chatId, describe(messages))
+}
+
+// Fails the test unless a message containing substring was sent to the chat
+func (s *Server) AssertSentMessageContains(tb testing.TB, chatId int64, substring string) {
+	tb.Helper()
+	messages := s.Calls(botan.MethodSendMessage)
+	for _, call := range messages {
+		if call.Int("chat_id") == chatId && strings.Contains(call.String("text"), substring) {
+			return
+		}
+	}
+	tb.Errorf("botantest: expected message containing %q to chat %d; sent messages: %s", substring, chatId, describe(messages))
+}
+
+func (s *Server) describeCalls() string {
+	return describe(s.Calls())
+}
+
+func describe(calls []Call) string {
+	if len(calls) == 0 {
+		return "none"
+	}
+	descriptions := make([]string, len(calls))
+	for i, call := range calls {
+		descriptions[i] = call.GoString()
+	}
+	return strings.Join(descriptions, "; ")
+}
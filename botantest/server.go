@@ -0,0 +1,492 @@
+// Package botantest provides a fake Telegram Bot API server for hermetic tests of bots built with botan.
+//
+//	srv := botantest.NewServer()
+//	defer srv.Close()
+//	bot := srv.Bot(t, &botan.BotCallbacksContainer{OnMessage: onMessage})
+//	srv.QueueMessage(42, "/start")
+//	go bot.Run(ctx)
+//	srv.WaitForCall(t, botan.MethodSendMessage, time.Second)
+//	srv.AssertSentMessage(t, 42, "Hello!")
+//
+// Every request is recorded as a Call. Responses can be scripted per method with Respond and Handle; otherwise
+// the server answers like Telegram would: getUpdates returns queued updates, send methods return a new Message
+// (sendMediaGroup returns one per media item), getFile and file downloads serve files added with AddFile,
+// the rest of methods return True.
+package botantest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/entities"
+)
+
+const (
+	DefaultToken   = "123456:TEST-TOKEN"
+	BotUserId      = 123456
+	BotUsername    = "test_bot"
+	maxLongPoll    = 5 * time.Second // getUpdates never blocks longer, so that tests finish quickly
+	maxUploadBytes = 32 << 20
+)
+
+// Response scripted for a method call
+type Response struct {
+	Result          interface{} // marshaled to JSON as the result of a successful call
+	ErrorCode       int         // non-zero for failed calls; also used as HTTP status code
+	Description     string      // error description
+	RetryAfter      int         // retry_after parameter of flood control errors
+	MigrateToChatId int64       // migrate_to_chat_id parameter
+}
+
+// Successful response with the given result
+func OK(result interface{}) Response {
+	return Response{Result: result}
+}
+
+// Failed response, e.g. Error(403, "Forbidden: bot was blocked by the user")
+func Error(code int, description string) Response {
+	return Response{ErrorCode: code, Description: description}
+}
+
+// Flood control error asking to wait retryAfter seconds
+func FloodWait(retryAfter int) Response {
+	return Response{
+		ErrorCode:   http.StatusTooManyRequests,
+		Description: fmt.Sprintf("Too Many Requests: retry after %d", retryAfter),
+		RetryAfter:  retryAfter,
+	}
+}
+
+// Fake Bot API server. Safe for concurrent use.
+type Server struct {
+	*httptest.Server
+	Token string // token the bot must use; requests with other tokens fail with 401
+
+	mu            sync.Mutex
+	calls         []Call
+	updates       []entities.Update
+	lastUpdateId  int
+	updatesQueued chan struct{} // closed and replaced when updates are queued
+	callRecorded  chan struct{} // closed and replaced when a call is recorded
+	closed        chan struct{}
+	scripted      map[string][]Response
+	handlers      map[string]func(call Call) Response
+	files         map[string]fakeFile // by file_id
+	lastMessageId int
+}
+
+type fakeFile struct {
+	path    string
+	content []byte
+}
+
+// Starts a fake server; call Close when done
+func NewServer() *Server {
+	s := Server{
+		Token:         DefaultToken,
+		updatesQueued: make(chan struct{}),
+		callRecorded:  make(chan struct{}),
+		closed:        make(chan struct{}),
+		scripted:      make(map[string][]Response),
+		handlers:      make(map[string]func(call Call) Response),
+		files:         make(map[string]fakeFile),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return &s
+}
+
+// Stops the server; pending getUpdates calls return immediately
+func (s *Server) Close() {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+	s.mu.Unlock()
+	s.Server.Close()
+}
+
+// Returns config for a bot talking to the server. Timeouts are short and retries are off, so that failures
+// show up fast; adjust the returned config if needed.
+func (s *Server) Config() *botan.Config {
+	return &botan.Config{
+		Token:                         s.Token,
+		APIBaseURL:                    s.URL,
+		LongPollTimeoutSeconds:        1,
+		GetUpdatesFailCooldownSeconds: 1,
+	}
+}
+
+// Creates a bot talking to the server; fails the test if the bot can't be created
+func (s *Server) Bot(tb testing.TB, callbacks *botan.BotCallbacksContainer) *botan.Bot {
+	tb.Helper()
+	bot, errBot := botan.NewBot(s.Config(), callbacks)
+	if errBot != nil {
+		tb.Fatalf("botantest: can't create bot: %v", errBot)
+	}
+	return bot
+}
+
+// Queues updates to be returned by getUpdates. Zero UpdateId is replaced with the next ID.
+func (s *Server) QueueUpdate(updates ...entities.Update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, update := range updates {
+		if update.UpdateId == 0 {
+			update.UpdateId = s.lastUpdateId + 1
+		}
+		if update.UpdateId > s.lastUpdateId {
+			s.lastUpdateId = update.UpdateId
+		}
+		s.updates = append(s.updates, update)
+	}
+	close(s.updatesQueued)
+	s.updatesQueued = make(chan struct{})
+}
+
+// Queues a text message from user with the same ID as chatId (a private chat). Commands at the start of text
+// get a bot_command entity, like Telegram does.
+func (s *Server) QueueMessage(chatId int, text string) {
+	s.QueueUpdate(entities.Update{Message: NewMessage(chatId, chatId, text)})
+}
+
+// Queues a callback query from the user with the given ID pressing a button under a message in private chat
+func (s *Server) QueueCallbackQuery(userId int, data string) {
+	s.mu.Lock()
+	s.lastMessageId++
+	messageId := s.lastMessageId
+	s.mu.Unlock()
+	s.QueueUpdate(entities.Update{CallbackQuery: &entities.CallbackQuery{
+		Id:           strconv.Itoa(messageId) + "-" + strconv.Itoa(userId),
+		Sender:       &entities.User{Id: userId, FirstName: "User"},
+		ChatInstance: strconv.Itoa(userId),
+		Message: &entities.Message{
+			MessageId: messageId,
+			Chat:      &entities.Chat{Id: userId, Type: "private"},
+		},
+		CallbackData: data,
+	}})
+}
+
+// Returns a text message in chatId from userId
+func NewMessage(chatId int, userId int, text string) *entities.Message {
+	chatType := "private"
+	if chatId < 0 {
+		chatType = "group"
+	}
+	date := entities.JsonUnixTime(time.Now())
+	msg := entities.Message{
+		From: &entities.User{Id: userId, FirstName: "User"},
+		Date: &date,
+		Chat: &entities.Chat{Id: chatId, Type: chatType},
+		Text: text,
+	}
+	if strings.HasPrefix(text, "/") {
+		command := strings.Fields(text)[0]
+		msg.Entities = []entities.MessageEntity{{
+			Type:   entities.MessageEntityTypeBotCommand,
+			Length: len(utf16.Encode([]rune(command))), // entity lengths are counted in UTF-16 code units
+		}}
+	}
+	return &msg
+}
+
+// Scripts responses for the next calls of method, in order. When they run out, the handler set with Handle
+// or the default behaviour is used again.
+func (s *Server) Respond(method string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripted[method] = append(s.scripted[method], responses...)
+}
+
+// Sets handler producing responses for all calls of method; nil restores the default behaviour
+func (s *Server) Handle(method string, handler func(call Call) Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if handler == nil {
+		delete(s.handlers, method)
+		return
+	}
+	s.handlers[method] = handler
+}
+
+// Adds a file to be returned by getFile and served for download
+func (s *Server) AddFile(fileId string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[fileId] = fakeFile{path: "documents/" + fileId, content: content}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/file/") {
+		s.serveFile(w, r)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "bot") {
+		writeResponse(w, Error(http.StatusNotFound, "Not Found"))
+		return
+	}
+	if parts[0] != "bot"+s.Token {
+		writeResponse(w, Error(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	call, errParse := parseCall(parts[1], r)
+	if errParse != nil {
+		writeResponse(w, Error(http.StatusBadRequest, "Bad Request: "+errParse.Error()))
+		return
+	}
+	s.record(call)
+	writeResponse(w, s.respond(r, call))
+}
+
+func (s *Server) record(call Call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+	close(s.callRecorded)
+	s.callRecorded = make(chan struct{})
+}
+
+func (s *Server) respond(r *http.Request, call Call) Response {
+	s.mu.Lock()
+	if scripted := s.scripted[call.Method]; len(scripted) > 0 {
+		s.scripted[call.Method] = scripted[1:]
+		s.mu.Unlock()
+		return scripted[0]
+	}
+	handler := s.handlers[call.Method]
+	s.mu.Unlock()
+	if handler != nil {
+		return handler(call)
+	}
+
+	switch {
+	case call.Method == botan.MethodGetUpdates:
+		return OK(s.pollUpdates(r, call))
+	case call.Method == botan.MethodGetMe:
+		return OK(entities.User{Id: BotUserId, IsBot: true, FirstName: "Test Bot", Username: BotUsername})
+	case call.Method == botan.MethodGetFile:
+		return s.getFile(call)
+	case call.Method == botan.MethodSendMediaGroup:
+		return s.sentMediaGroup(call)
+	case strings.HasPrefix(call.Method, "send") || call.Method == botan.MethodForwardMessage:
+		return OK(s.sentMessage(call))
+	}
+	return OK(true)
+}
+
+// Returns updates after offset, waiting for them up to timeout. Updates before offset are confirmed and dropped.
+func (s *Server) pollUpdates(r *http.Request, call Call) []entities.Update {
+	offset := int(call.Int("offset"))
+	timeout := time.Duration(call.Int("timeout")) * time.Second
+	if timeout > maxLongPoll {
+		timeout = maxLongPoll
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		s.mu.Lock()
+		confirmed := 0
+		for confirmed < len(s.updates) && s.updates[confirmed].UpdateId < offset {
+			confirmed++
+		}
+		s.updates = s.updates[confirmed:]
+		updates := append([]entities.Update{}, s.updates...)
+		queued := s.updatesQueued
+		s.mu.Unlock()
+
+		if len(updates) > 0 {
+			return updates
+		}
+		select {
+		case <-queued:
+		case <-deadline.C:
+			return updates
+		case <-r.Context().Done():
+			return updates
+		case <-s.closed:
+			return updates
+		}
+	}
+}
+
+// Message returned by send methods: chat and text or caption are taken from the call
+func (s *Server) sentMessage(call Call) map[string]interface{} {
+	msg := s.newSentMessage(call)
+	if text := call.String("text"); text != "" {
+		msg["text"] = text
+	}
+	if caption := call.String("caption"); caption != "" {
+		msg["caption"] = caption
+	}
+	return msg
+}
+
+// Messages returned by sendMediaGroup, one per item of media; captions are taken from the items
+func (s *Server) sentMediaGroup(call Call) Response {
+	media, ok := call.Params["media"].([]interface{})
+	if !ok || len(media) == 0 {
+		return Error(http.StatusBadRequest, "Bad Request: media must be a non-empty array")
+	}
+	s.mu.Lock()
+	mediaGroupId := strconv.Itoa(s.lastMessageId + 1)
+	s.mu.Unlock()
+
+	messages := make([]map[string]interface{}, 0, len(media))
+	for _, item := range media {
+		msg := s.newSentMessage(call)
+		msg["media_group_id"] = mediaGroupId
+		if fields, isObject := item.(map[string]interface{}); isObject {
+			if caption, isString := fields["caption"].(string); isString && caption != "" {
+				msg["caption"] = caption
+			}
+		}
+		messages = append(messages, msg)
+	}
+	return OK(messages)
+}
+
+// Message with a new ID sent by the bot to the chat of the call
+func (s *Server) newSentMessage(call Call) map[string]interface{} {
+	s.mu.Lock()
+	s.lastMessageId++
+	messageId := s.lastMessageId
+	s.mu.Unlock()
+
+	chat := map[string]interface{}{"id": call.Params["chat_id"], "type": "private"}
+	if chatId := call.Int("chat_id"); chatId < 0 {
+		chat["type"] = "group"
+	}
+	msg := map[string]interface{}{
+		"message_id": messageId,
+		"date":       time.Now().Unix(),
+		"chat":       chat,
+		"from":       map[string]interface{}{"id": BotUserId, "is_bot": true, "first_name": "Test Bot", "username": BotUsername},
+	}
+	return msg
+}
+
+func (s *Server) getFile(call Call) Response {
+	fileId := call.String("file_id")
+	s.mu.Lock()
+	file, found := s.files[fileId]
+	s.mu.Unlock()
+	if !found {
+		return Error(http.StatusBadRequest, "Bad Request: invalid file_id")
+	}
+	return OK(entities.File{FileId: fileId, FileSize: len(file.content), FilePath: file.path})
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request) {
+	prefix := "/file/bot" + s.Token + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	filePath := strings.TrimPrefix(r.URL.Path, prefix)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, file := range s.files {
+		if file.path == filePath {
+			w.Write(file.content)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	body := map[string]interface{}{"ok": response.ErrorCode == 0}
+	status := http.StatusOK
+	if response.ErrorCode == 0 {
+		body["result"] = response.Result
+	} else {
+		status = response.ErrorCode
+		body["error_code"] = response.ErrorCode
+		body["description"] = response.Description
+		parameters := map[string]interface{}{}
+		if response.RetryAfter > 0 {
+			parameters["retry_after"] = response.RetryAfter
+		}
+		if response.MigrateToChatId != 0 {
+			parameters["migrate_to_chat_id"] = response.MigrateToChatId
+		}
+		if len(parameters) > 0 {
+			body["parameters"] = parameters
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Parses parameters of a call from query string, JSON or form body
+func parseCall(method string, r *http.Request) (Call, error) {
+	call := Call{Method: method, Params: make(map[string]interface{}), Time: time.Now()}
+	for name, values := range r.URL.Query() {
+		call.Params[name] = parseFormValue(values[0])
+	}
+	if r.Method != http.MethodPost {
+		return call, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		body, errRead := io.ReadAll(r.Body)
+		if errRead != nil {
+			return call, errRead
+		}
+		if len(body) > 0 && string(body) != "null" {
+			if errUnmarshal := json.Unmarshal(body, &call.Params); errUnmarshal != nil {
+				return call, errUnmarshal
+			}
+		}
+	case "multipart/form-data":
+		if errParse := r.ParseMultipartForm(maxUploadBytes); errParse != nil {
+			return call, errParse
+		}
+		for name, values := range r.MultipartForm.Value {
+			call.Params[name] = parseFormValue(values[0])
+		}
+		call.Files = make(map[string]UploadedFile)
+		for name, headers := range r.MultipartForm.File {
+			file, errOpen := headers[0].Open()
+			if errOpen != nil {
+				return call, errOpen
+			}
+			content, errRead := io.ReadAll(file)
+			file.Close()
+			if errRead != nil {
+				return call, errRead
+			}
+			call.Files[name] = UploadedFile{Name: headers[0].Filename, Content: content}
+		}
+	}
+	return call, nil
+}
+
+// Form values of multipart requests are JSON-serialized for complex types; plain strings are sent as is
+func parseFormValue(value string) interface{} {
+	var parsed interface{}
+	if errUnmarshal := json.Unmarshal([]byte(value), &parsed); errUnmarshal == nil {
+		return parsed
+	}
+	return value
+}
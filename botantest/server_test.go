@@ -0,0 +1,162 @@
+package botantest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+	"github.com/isvinogradov/botan/entities"
+)
+
+func newBot(t *testing.T, callbacks *botan.BotCallbacksContainer) (*botantest.Server, *botan.Bot) {
+	t.Helper()
+	srv := botantest.NewServer()
+	t.Cleanup(srv.Close)
+	if callbacks == nil {
+		callbacks = &botan.BotCallbacksContainer{}
+	}
+	return srv, srv.Bot(t, callbacks)
+}
+
+func TestGetMe(t *testing.T) {
+	_, bot := newBot(t, nil)
+	me, errMe := bot.GetMe()
+	if errMe != nil {
+		t.Fatal(errMe)
+	}
+	if me.Id != botantest.BotUserId || me.Username != botantest.BotUsername {
+		t.Errorf("got %+v", me)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	srv, bot := newBot(t, nil)
+	msg, errSend := bot.SendMessage(&botan.SendMessageRequest{ChatId: 42, Text: "hello"})
+	if errSend != nil {
+		t.Fatal(errSend)
+	}
+	if msg.MessageId == 0 || msg.Chat.Id != 42 || msg.Text != "hello" {
+		t.Errorf("got %+v", msg)
+	}
+	srv.AssertSentMessage(t, 42, "hello")
+}
+
+func TestSendMediaGroup(t *testing.T) {
+	srv, bot := newBot(t, nil)
+	path := filepath.Join(t.TempDir(), "photo.jpg")
+	if errWrite := os.WriteFile(path, []byte("jpeg"), 0o600); errWrite != nil {
+		t.Fatal(errWrite)
+	}
+
+	messages, errSend := bot.SendMediaGroup(&botan.SendMediaGroupRequest{
+		ChatId: 42,
+		Media: []entities.InputMedia{
+			{ExactType: entities.InputMediaPhoto{Type: "photo", Media: entities.InputFileFromPath(path), Caption: "first"}},
+			{ExactType: entities.InputMediaPhoto{Type: "photo", Media: entities.InputFileFromId("abc")}},
+		},
+	})
+	if errSend != nil {
+		t.Fatal(errSend)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("got %d messages, want 2", len(messages))
+	}
+	if messages[0].Caption != "first" || messages[0].MediaGroupId == "" || messages[0].MediaGroupId != messages[1].MediaGroupId {
+		t.Errorf("got %+v and %+v", messages[0], messages[1])
+	}
+	call, _ := srv.LastCall(botan.MethodSendMediaGroup)
+	if file := call.Files["file0"]; !bytes.Equal(file.Content, []byte("jpeg")) {
+		t.Errorf("uploaded %+v", call.Files)
+	}
+}
+
+func TestOtherMethodsReturnTrue(t *testing.T) {
+	srv, bot := newBot(t, nil)
+	ok, errDelete := bot.DeleteMessage(&botan.DeleteMessageRequest{ChatId: 42, MessageId: 1})
+	if errDelete != nil || !ok {
+		t.Fatalf("got %v, %v", ok, errDelete)
+	}
+	srv.AssertCalled(t, botan.MethodDeleteMessage)
+}
+
+func TestGetUpdates(t *testing.T) {
+	received := make(chan *entities.Message, 1)
+	srv, bot := newBot(t, &botan.BotCallbacksContainer{
+		OnMessage: func(bot *botan.Bot, msg *entities.Message) error {
+			received <- msg
+			return nil
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		bot.Run(ctx)
+		close(stopped)
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+
+	srv.QueueMessage(42, "/start")
+	select {
+	case msg := <-received:
+		if msg.Chat.Id != 42 || msg.Text != "/start" {
+			t.Errorf("got %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued message not received")
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	srv, bot := newBot(t, nil)
+	srv.AddFile("doc", []byte("contents"))
+	var buf bytes.Buffer
+	if errDownload := bot.DownloadFile("doc", &buf); errDownload != nil {
+		t.Fatal(errDownload)
+	}
+	if buf.String() != "contents" {
+		t.Errorf("downloaded %q", buf.String())
+	}
+	if _, errMissing := bot.GetFile(&botan.GetFileRequest{FileId: "missing"}); errMissing == nil {
+		t.Error("getFile of unknown file succeeded")
+	}
+}
+
+func TestScriptedError(t *testing.T) {
+	srv, bot := newBot(t, nil)
+	srv.Respond(botan.MethodSendMessage, botantest.Error(http.StatusForbidden, "Forbidden: bot was blocked by the user"))
+
+	_, errSend := bot.SendMessage(&botan.SendMessageRequest{ChatId: 42, Text: "hello"})
+	var apiErr *botan.APIError
+	if !errors.As(errSend, &apiErr) || !apiErr.IsForbidden() {
+		t.Fatalf("got %v, want forbidden APIError", errSend)
+	}
+	// scripted responses are used once
+	if _, errSend = bot.SendMessage(&botan.SendMessageRequest{ChatId: 42, Text: "hello"}); errSend != nil {
+		t.Fatal(errSend)
+	}
+}
+
+func TestWrongToken(t *testing.T) {
+	srv, _ := newBot(t, nil)
+	conf := srv.Config()
+	conf.Token = "1:wrong"
+	bot, errBot := botan.NewBot(conf, &botan.BotCallbacksContainer{})
+	if errBot != nil {
+		t.Fatal(errBot)
+	}
+	_, errMe := bot.GetMe()
+	var apiErr *botan.APIError
+	if !errors.As(errMe, &apiErr) || apiErr.ErrorCode != http.StatusUnauthorized {
+		t.Fatalf("got %v, want 401 APIError", errMe)
+	}
+}
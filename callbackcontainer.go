@@ -17,6 +17,13 @@ type BotCallbacksContainer struct {
 	OnEditedChannelPost  func(bot *Bot, msg *en.Message) error            // New channel post edit received
 	OnChosenInlineResult func(bot *Bot, cir *en.ChosenInlineResult) error // New result for inline query received
 	OnPoll               func(bot *Bot, poll *en.Poll) error              // Poll vote received
+	OnShippingQuery      func(bot *Bot, sq *en.ShippingQuery) error       // Shipping query received; only for invoices with flexible price. Answer with AnswerShippingQuery
+	OnPreCheckoutQuery   func(bot *Bot, pcq *en.PreCheckoutQuery) error   // Pre-checkout query received. Answer with AnswerPreCheckoutQuery within 10 seconds
+	OnCallbackGame       func(bot *Bot, cbq *en.CallbackQuery) error      // Game launch requested with callback_game button (GameShortName is set). Answer with AnswerCallbackQuery passing the game URL. If not set, game launches go to OnCallbackQuery
+
+	// Wrappers applied to handlers of all update types, e.g. for logging, metrics or access control.
+	// The first middleware is the outermost one: it's called first and gets the result of the rest of the chain.
+	Middleware []Middleware
 
 	// Handlers for storing offset in an external source like database or file
 	OnGetOffset    func() int            // Get offset from external source
@@ -27,24 +34,23 @@ type BotCallbacksContainer struct {
 }
 
 // if no callbacks for getting and storing offset were provided, then generate empty functions
-func (cbCont *BotCallbacksContainer) checkAndInit() {
+func (cbCont *BotCallbacksContainer) checkAndInit(log *botLogger) {
 	if cbCont.OnGetOffset == nil {
-		fmt.Println("GetOffset callback missing")
+		log.warn("callback missing", "callback", "OnGetOffset")
 		cbCont.OnGetOffset = func() int { return 0 }
 	}
 	if cbCont.OnSetNewOffset == nil {
-		fmt.Println("SetOffset callback missing")
+		log.warn("callback missing", "callback", "OnSetNewOffset")
 		cbCont.OnSetNewOffset = func(newUpdateId int) {}
 	}
 	if cbCont.OnError == nil {
-		fmt.Println("OnError callback missing")
+		log.warn("callback missing", "callback", "OnError")
 		cbCont.OnError = func(err error) {} // don't stop on errors
 	}
 }
 
-// returns a string which represents types of updates bot can receive
-// in form of: "[\"message\", \"callback_query\", ...]"
-func (cbCont *BotCallbacksContainer) generateAllowedUpdates() string {
+// returns types of updates bot can receive according to the callbacks set
+func (cbCont *BotCallbacksContainer) allowedUpdatesList() []string {
 	var availableCallbacks []string
 
 	if cbCont.OnMessage != nil {
@@ -53,7 +59,7 @@ func (cbCont *BotCallbacksContainer) generateAllowedUpdates() string {
 	if cbCont.OnEditedMessage != nil {
 		availableCallbacks = append(availableCallbacks, "edited_message")
 	}
-	if cbCont.OnCallbackQuery != nil {
+	if cbCont.OnCallbackQuery != nil || cbCont.OnCallbackGame != nil {
 		availableCallbacks = append(availableCallbacks, "callback_query")
 	}
 	if cbCont.OnInlineQuery != nil {
@@ -71,7 +77,19 @@ func (cbCont *BotCallbacksContainer) generateAllowedUpdates() string {
 	if cbCont.OnPoll != nil {
 		availableCallbacks = append(availableCallbacks, "poll")
 	}
+	if cbCont.OnShippingQuery != nil {
+		availableCallbacks = append(availableCallbacks, "shipping_query")
+	}
+	if cbCont.OnPreCheckoutQuery != nil {
+		availableCallbacks = append(availableCallbacks, "pre_checkout_query")
+	}
+	return availableCallbacks
+}
 
+// returns a string which represents types of updates bot can receive
+// in form of: "[\"message\", \"callback_query\", ...]"
+func (cbCont *BotCallbacksContainer) generateAllowedUpdates() string {
+	availableCallbacks := cbCont.allowedUpdatesList()
 	for i := range availableCallbacks {
 		availableCallbacks[i] = fmt.Sprintf("\"%s\"", availableCallbacks[i])
 	}
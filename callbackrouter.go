@@ -0,0 +1,347 @@
+package botan
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	en "github.com/isvinogradov/botan/entities"
+)
+
+const (
+	MaxCallbackDataLength = 64 // limit of InlineKeyboardButton.CallbackData in bytes
+
+	callbackDataSeparator   = ":"
+	storedCallbackDataMark  = "~" // data starting with it is a key of payload kept in CallbackDataStorage
+	defaultCallbackDataTTL  = 48 * time.Hour
+	callbackStorageKeyBytes = 9 // 12 characters in base64
+)
+
+var (
+	ErrCallbackDataTooLong = fmt.Errorf("callback data exceeds %d bytes", MaxCallbackDataLength)
+
+	callbackDataEscaper   = strings.NewReplacer("%", "%25", callbackDataSeparator, "%3A")
+	callbackDataUnescaper = strings.NewReplacer("%25", "%", "%3A", callbackDataSeparator)
+)
+
+// Result of matching callback data against a route
+type CallbackMatch struct {
+	Data    string   // full callback data; payloads kept in storage are already resolved
+	Payload string   // data following the prefix for HandlePrefix routes
+	Groups  []string // submatches of the regular expression for HandlePattern routes, Groups[0] is the whole match
+}
+
+// Decodes payload encoded with EncodeCallbackData into target
+func (cm *CallbackMatch) Decode(target interface{}) error {
+	return DecodeCallbackData(cm.Data, target)
+}
+
+// Handles a callback query; match is never nil
+type CallbackHandler func(bot *Bot, cbq *en.CallbackQuery, match *CallbackMatch) error
+
+type callbackRoute struct {
+	prefix  string
+	pattern *regexp.Regexp
+	handler CallbackHandler
+}
+
+// Keeps callback data which doesn't fit into 64 bytes. Implement it on top of a shared storage (e.g. Redis)
+// if buttons must keep working after restart or across several bot processes.
+type CallbackDataStorage interface {
+	Store(key string, data string) error
+	Load(key string) (string, bool, error) // false if key is not found or expired
+}
+
+// Routes callback queries to handlers by CallbackData. Routes are checked in order of registration, the first match
+// wins. Set its OnCallbackQuery method as BotCallbacksContainer.OnCallbackQuery:
+//
+//	router := botan.NewCallbackRouter()
+//	router.HandlePrefix("vote:", onVote)
+//	data, err := router.EncodeData("vote", votePayload{PollId: 12, Option: 3}) // "vote:c:3"
+type CallbackRouter struct {
+	NotFound func(bot *Bot, cbq *en.CallbackQuery) error // Optional. Called if no route matches or stored data expired; by default the query is answered silently so that the client stops showing progress
+	Storage  CallbackDataStorage                         // Optional. Keeps payloads longer than 64 bytes; EncodeData fails with ErrCallbackDataTooLong for them if not set
+
+	mu     sync.RWMutex
+	routes []callbackRoute
+}
+
+func NewCallbackRouter() *CallbackRouter {
+	return &CallbackRouter{}
+}
+
+// Registers handler for callback data starting with prefix
+func (cr *CallbackRouter) HandlePrefix(prefix string, handler CallbackHandler) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.routes = append(cr.routes, callbackRoute{prefix: prefix, handler: handler})
+}
+
+// Registers handler for callback data matching regular expression
+func (cr *CallbackRouter) HandlePattern(expr string, handler CallbackHandler) error {
+	pattern, errCompile := regexp.Compile(expr)
+	if errCompile != nil {
+		return errCompile
+	}
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.routes = append(cr.routes, callbackRoute{pattern: pattern, handler: handler})
+	return nil
+}
+
+// Routes the callback query to the respective handler
+func (cr *CallbackRouter) OnCallbackQuery(bot *Bot, cbq *en.CallbackQuery) error {
+	data := cbq.CallbackData
+	if cr.Storage != nil && strings.HasPrefix(data, storedCallbackDataMark) {
+		storedData, found, errLoad := cr.Storage.Load(strings.TrimPrefix(data, storedCallbackDataMark))
+		if errLoad != nil {
+			return errLoad
+		}
+		if !found {
+			return cr.notFound(bot, cbq)
+		}
+		data = storedData
+	}
+
+	// handler is called without the lock, so that it may register new routes
+	handler, match := cr.match(data)
+	if handler == nil {
+		return cr.notFound(bot, cbq)
+	}
+	return handler(bot, cbq, match)
+}
+
+// Returns handler of the first route matching data; nil if there's none
+func (cr *CallbackRouter) match(data string) (CallbackHandler, *CallbackMatch) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	for _, route := range cr.routes {
+		if route.pattern != nil {
+			if groups := route.pattern.FindStringSubmatch(data); groups != nil {
+				return route.handler, &CallbackMatch{Data: data, Groups: groups}
+			}
+			continue
+		}
+		if strings.HasPrefix(data, route.prefix) {
+			return route.handler, &CallbackMatch{Data: data, Payload: strings.TrimPrefix(data, route.prefix)}
+		}
+	}
+	return nil, nil
+}
+
+func (cr *CallbackRouter) notFound(bot *Bot, cbq *en.CallbackQuery) error {
+	if cr.NotFound != nil {
+		return cr.NotFound(bot, cbq)
+	}
+	_, errAnswer := bot.AnswerCallbackQuery(&AnswerCallbackQueryRequest{CallbackQueryId: cbq.Id})
+	return errAnswer
+}
+
+// Encodes payload with EncodeCallbackData. If the result exceeds 64 bytes, it's put to Storage and a short key
+// is returned instead.
+func (cr *CallbackRouter) EncodeData(prefix string, payload interface{}) (string, error) {
+	data, errEncode := encodeCallbackData(prefix, payload)
+	if errEncode != nil {
+		return "", errEncode
+	}
+	if len(data) <= MaxCallbackDataLength {
+		return data, nil
+	}
+	if cr.Storage == nil {
+		return "", ErrCallbackDataTooLong
+	}
+
+	keyBytes := make([]byte, callbackStorageKeyBytes)
+	if _, errRand := rand.Read(keyBytes); errRand != nil {
+		return "", errRand
+	}
+	key := base64.RawURLEncoding.EncodeToString(keyBytes)
+	if errStore := cr.Storage.Store(key, data); errStore != nil {
+		return "", errStore
+	}
+	return storedCallbackDataMark + key, nil
+}
+
+// Encodes payload to compact callback data: prefix followed by exported fields of the payload struct separated
+// by colons, e.g. "vote:c:1". Integers are written in base 36, booleans as 0 or 1. Supported field kinds are
+// strings, booleans, integers and floats. Payload may be nil, then data consists of the prefix only.
+// Returns ErrCallbackDataTooLong if the result exceeds 64 bytes.
+func EncodeCallbackData(prefix string, payload interface{}) (string, error) {
+	data, errEncode := encodeCallbackData(prefix, payload)
+	if errEncode != nil {
+		return "", errEncode
+	}
+	if len(data) > MaxCallbackDataLength {
+		return "", ErrCallbackDataTooLong
+	}
+	return data, nil
+}
+
+func encodeCallbackData(prefix string, payload interface{}) (string, error) {
+	if strings.Contains(prefix, callbackDataSeparator) || strings.HasPrefix(prefix, storedCallbackDataMark) {
+		return "", fmt.Errorf("callback data prefix %q must not contain %q or start with %q",
+			prefix, callbackDataSeparator, storedCallbackDataMark)
+	}
+	if payload == nil {
+		return prefix, nil
+	}
+	v := reflect.Indirect(reflect.ValueOf(payload))
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("callback data payload must be a struct, got %s", v.Kind())
+	}
+
+	var data strings.Builder
+	data.WriteString(prefix)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath != "" {
+			continue // unexported
+		}
+		data.WriteString(callbackDataSeparator)
+		fv := v.Field(i)
+		switch fv.Kind() {
+		case reflect.String:
+			data.WriteString(callbackDataEscaper.Replace(fv.String()))
+		case reflect.Bool:
+			if fv.Bool() {
+				data.WriteString("1")
+			} else {
+				data.WriteString("0")
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			data.WriteString(strconv.FormatInt(fv.Int(), 36))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			data.WriteString(strconv.FormatUint(fv.Uint(), 36))
+		case reflect.Float32, reflect.Float64:
+			data.WriteString(strconv.FormatFloat(fv.Float(), 'g', -1, fv.Type().Bits()))
+		default:
+			return "", fmt.Errorf("unsupported kind %s of callback data field %s", fv.Kind(), v.Type().Field(i).Name)
+		}
+	}
+	return data.String(), nil
+}
+
+// Decodes callback data produced by EncodeCallbackData into target, which must be a pointer to a struct of the same
+// type as the encoded payload. Prefix is skipped.
+func DecodeCallbackData(data string, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("callback data target must be a non-nil pointer to a struct")
+	}
+	v = v.Elem()
+
+	values := strings.Split(data, callbackDataSeparator)[1:] // skip prefix
+	next := 0
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath != "" {
+			continue
+		}
+		if next >= len(values) {
+			return fmt.Errorf("callback data %q has too few fields", data)
+		}
+		value := values[next]
+		next++
+
+		fv := v.Field(i)
+		var errParse error
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(callbackDataUnescaper.Replace(value))
+		case reflect.Bool:
+			fv.SetBool(value == "1")
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var n int64
+			if n, errParse = strconv.ParseInt(value, 36, fv.Type().Bits()); errParse == nil {
+				fv.SetInt(n)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			var n uint64
+			if n, errParse = strconv.ParseUint(value, 36, fv.Type().Bits()); errParse == nil {
+				fv.SetUint(n)
+			}
+		case reflect.Float32, reflect.Float64:
+			var f float64
+			if f, errParse = strconv.ParseFloat(value, fv.Type().Bits()); errParse == nil {
+				fv.SetFloat(f)
+			}
+		default:
+			return fmt.Errorf("unsupported kind %s of callback data field %s", fv.Kind(), v.Type().Field(i).Name)
+		}
+		if errParse != nil {
+			return fmt.Errorf("callback data field %s: %w", v.Type().Field(i).Name, errParse)
+		}
+	}
+	if next != len(values) {
+		return fmt.Errorf("callback data %q has too many fields", data)
+	}
+	return nil
+}
+
+// In-memory CallbackDataStorage. Data is dropped after TTL, so buttons of old messages stop working;
+// data is lost on restart. The zero value is ready to use.
+type MemoryCallbackDataStorage struct {
+	TTL time.Duration // defaults to 48 hours
+
+	mu        sync.Mutex
+	entries   map[string]storedCallbackData
+	lastSweep time.Time
+}
+
+type storedCallbackData struct {
+	data    string
+	expires time.Time
+}
+
+func NewMemoryCallbackDataStorage() *MemoryCallbackDataStorage {
+	return &MemoryCallbackDataStorage{
+		TTL:       defaultCallbackDataTTL,
+		entries:   make(map[string]storedCallbackData),
+		lastSweep: time.Now(),
+	}
+}
+
+func (ms *MemoryCallbackDataStorage) Store(key string, data string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	now := time.Now()
+	if ms.entries == nil {
+		ms.entries = make(map[string]storedCallbackData)
+		ms.lastSweep = now
+	}
+	ms.sweep(now)
+	ttl := ms.TTL
+	if ttl <= 0 {
+		ttl = defaultCallbackDataTTL
+	}
+	ms.entries[key] = storedCallbackData{data: data, expires: now.Add(ttl)}
+	return nil
+}
+
+func (ms *MemoryCallbackDataStorage) Load(key string) (string, bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	entry, found := ms.entries[key]
+	if !found || time.Now().After(entry.expires) {
+		return "", false, nil
+	}
+	return entry.data, true, nil
+}
+
+// drops expired entries, at most once a minute
+func (ms *MemoryCallbackDataStorage) sweep(now time.Time) {
+	if now.Sub(ms.lastSweep) < time.Minute {
+		return
+	}
+	ms.lastSweep = now
+	for key, entry := range ms.entries {
+		if now.After(entry.expires) {
+			delete(ms.entries, key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package botan_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/entities"
+)
+
+type notePayload struct {
+	NoteId int
+	Text   string
+}
+
+func TestCallbackRouterStoresLongData(t *testing.T) {
+	router := botan.NewCallbackRouter()
+	router.Storage = &botan.MemoryCallbackDataStorage{TTL: time.Hour} // zero value apart from TTL
+
+	var got notePayload
+	router.HandlePrefix("note:", func(bot *botan.Bot, cbq *entities.CallbackQuery, match *botan.CallbackMatch) error {
+		return match.Decode(&got)
+	})
+
+	want := notePayload{NoteId: 1000, Text: strings.Repeat("long: text ", 10)}
+	data, errEncode := router.EncodeData("note", want)
+	if errEncode != nil {
+		t.Fatal(errEncode)
+	}
+	if len(data) > botan.MaxCallbackDataLength || !strings.HasPrefix(data, "~") {
+		t.Fatalf("data %q isn't a storage key", data)
+	}
+
+	if errRoute := router.OnCallbackQuery(nil, &entities.CallbackQuery{Id: "q", CallbackData: data}); errRoute != nil {
+		t.Fatal(errRoute)
+	}
+	if got != want {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestCallbackHandlerRegistersRoute(t *testing.T) {
+	router := botan.NewCallbackRouter()
+	var got []string
+	router.HandlePrefix("start:", func(bot *botan.Bot, cbq *entities.CallbackQuery, match *botan.CallbackMatch) error {
+		// e.g. a wizard adding routes for the buttons of the next step
+		router.HandlePrefix("step:", func(bot *botan.Bot, cbq *entities.CallbackQuery, match *botan.CallbackMatch) error {
+			got = append(got, match.Data)
+			return nil
+		})
+		got = append(got, match.Data)
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		errStart := router.OnCallbackQuery(nil, &entities.CallbackQuery{CallbackData: "start:1"})
+		if errStart == nil {
+			errStart = router.OnCallbackQuery(nil, &entities.CallbackQuery{CallbackData: "step:2"})
+		}
+		done <- errStart
+	}()
+	select {
+	case errRoute := <-done:
+		if errRoute != nil {
+			t.Fatal(errRoute)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler registering a route deadlocked")
+	}
+	if strings.Join(got, ",") != "start:1,step:2" {
+		t.Errorf("handled %q", got)
+	}
+}
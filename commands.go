@@ -0,0 +1,200 @@
+package botan
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	en "github.com/isvinogradov/botan/entities"
+)
+
+const helpCommand = "help"
+
+// command names allowed by Telegram
+var commandNameRegexp = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// Command parsed from a message
+type Command struct {
+	Name    string   // command name without leading slash and @botname suffix, lowercase
+	Args    []string // arguments split by whitespace
+	RawArgs string   // text following the command, trimmed
+}
+
+// Handles a command; cmd is never nil
+type CommandHandler func(bot *Bot, msg *en.Message, cmd *Command) error
+
+type commandRoute struct {
+	description string
+	handler     CommandHandler
+}
+
+// Routes messages starting with a /command to handlers registered with Handle. Set its OnMessage method as
+// BotCallbacksContainer.OnMessage:
+//
+//	router := botan.NewCommandRouter()
+//	router.Handle("start", "Start using the bot", onStart)
+//	callbacks := botan.BotCallbacksContainer{OnMessage: router.OnMessage}
+//
+// Commands addressed to other bots (/command@otherbot) are ignored. If no "help" command is registered,
+// /help replies with the list of registered commands. The zero value is ready to use.
+type CommandRouter struct {
+	NotFound  CommandHandler                        // Optional. Called for unknown commands; by default the bot replies with a hint to use /help
+	NoCommand func(bot *Bot, msg *en.Message) error // Optional. Called for messages which don't start with a command; such messages are ignored by default
+
+	mu          sync.Mutex
+	routes      map[string]*commandRoute
+	order       []string // command names in order of registration
+	botUsername string   // cached result of GetMe
+}
+
+func NewCommandRouter() *CommandRouter {
+	return &CommandRouter{routes: make(map[string]*commandRoute)}
+}
+
+// Registers handler for the command. Name is case-insensitive and may be given with or without leading slash.
+// Description is shown in /help and synced to Telegram by SyncCommands. Registering the same name again
+// replaces the handler.
+func (cr *CommandRouter) Handle(name string, description string, handler CommandHandler) error {
+	name = strings.ToLower(strings.TrimPrefix(name, "/"))
+	if !commandNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid command name %q: use 1-32 lowercase English letters, digits and underscores", name)
+	}
+	if handler == nil {
+		return fmt.Errorf("nil handler for command %s", name)
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if cr.routes == nil {
+		cr.routes = make(map[string]*commandRoute) // zero value router
+	}
+	if _, exists := cr.routes[name]; !exists {
+		cr.order = append(cr.order, name)
+	}
+	cr.routes[name] = &commandRoute{description: description, handler: handler}
+	return nil
+}
+
+// Routes the message to the respective command handler
+func (cr *CommandRouter) OnMessage(bot *Bot, msg *en.Message) error {
+	cmd, mention, ok := ParseCommand(msg)
+	if !ok {
+		if cr.NoCommand != nil {
+			return cr.NoCommand(bot, msg)
+		}
+		return nil
+	}
+	if mention != "" {
+		username, errMe := cr.username(bot)
+		if errMe != nil {
+			return errMe
+		}
+		if !strings.EqualFold(mention, username) {
+			return nil // command for another bot in the group
+		}
+	}
+
+	cr.mu.Lock()
+	route, found := cr.routes[cmd.Name]
+	cr.mu.Unlock()
+	switch {
+	case found:
+		return route.handler(bot, msg, cmd)
+	case cmd.Name == helpCommand:
+		return cr.sendHelp(bot, msg)
+	case cr.NotFound != nil:
+		return cr.NotFound(bot, msg, cmd)
+	}
+	_, errSend := bot.SendMessage(&SendMessageRequest{
+		ChatId:           msg.Chat.Id,
+		Text:             fmt.Sprintf("Unknown command /%s. Send /%s for the list of commands.", cmd.Name, helpCommand),
+		ReplyToMessageId: msg.MessageId,
+	})
+	return errSend
+}
+
+// Sends descriptions of the registered commands to Telegram, so that clients suggest them to users
+func (cr *CommandRouter) SyncCommands(bot *Bot) error {
+	_, errSet := bot.SetMyCommands(&SetMyCommandsRequest{Commands: cr.Commands()})
+	return errSet
+}
+
+// Returns registered commands in order of registration
+func (cr *CommandRouter) Commands() []en.BotCommand {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	commands := make([]en.BotCommand, 0, len(cr.order)+1)
+	for _, name := range cr.order {
+		commands = append(commands, en.BotCommand{Command: name, Description: cr.routes[name].description})
+	}
+	if _, hasHelp := cr.routes[helpCommand]; !hasHelp {
+		commands = append(commands, en.BotCommand{Command: helpCommand, Description: "List available commands"})
+	}
+	return commands
+}
+
+func (cr *CommandRouter) sendHelp(bot *Bot, msg *en.Message) error {
+	commands := cr.Commands()
+	sort.SliceStable(commands, func(i, j int) bool { return commands[i].Command < commands[j].Command })
+	var text strings.Builder
+	text.WriteString("Available commands:")
+	for _, c := range commands {
+		fmt.Fprintf(&text, "\n/%s - %s", c.Command, c.Description)
+	}
+	_, errSend := bot.SendMessage(&SendMessageRequest{ChatId: msg.Chat.Id, Text: text.String()})
+	return errSend
+}
+
+// Returns the bot's username; GetMe is called until it succeeds once. The lock isn't held during the call, so
+// routing of other messages doesn't wait for it.
+func (cr *CommandRouter) username(bot *Bot) (string, error) {
+	cr.mu.Lock()
+	username := cr.botUsername
+	cr.mu.Unlock()
+	if username != "" {
+		return username, nil
+	}
+
+	me, errMe := bot.GetMe()
+	if errMe != nil {
+		return "", errMe
+	}
+	cr.mu.Lock()
+	cr.botUsername = me.Username
+	cr.mu.Unlock()
+	return me.Username, nil
+}
+
+// Parses the command the message text starts with. Returns the command, bot username it's addressed to
+// (empty if there's no @botname suffix) and false if the message doesn't start with a command.
+// Entity offsets are measured in UTF-16 code units, so text is converted before slicing.
+func ParseCommand(msg *en.Message) (*Command, string, bool) {
+	if msg == nil {
+		return nil, "", false
+	}
+	for _, entity := range msg.Entities {
+		if entity.Type != en.MessageEntityTypeBotCommand || entity.Offset != 0 {
+			continue
+		}
+		text := utf16.Encode([]rune(msg.Text))
+		if entity.Length < 2 || entity.Length > len(text) {
+			return nil, "", false
+		}
+		name := string(utf16.Decode(text[1:entity.Length])) // skip leading slash
+		rawArgs := strings.TrimSpace(string(utf16.Decode(text[entity.Length:])))
+
+		var mention string
+		if at := strings.IndexByte(name, '@'); at >= 0 {
+			name, mention = name[:at], name[at+1:]
+		}
+		return &Command{
+			Name:    strings.ToLower(name),
+			Args:    strings.Fields(rawArgs),
+			RawArgs: rawArgs,
+		}, mention, true
+	}
+	return nil, "", false
+}
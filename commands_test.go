@@ -0,0 +1,96 @@
+package botan_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+	"github.com/isvinogradov/botan/entities"
+)
+
+func TestCommandRouterDoesntWaitForGetMe(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+	release := make(chan struct{})
+	releaseGetMe := sync.OnceFunc(func() { close(release) })
+	defer releaseGetMe() // before srv.Close, which waits for the pending getMe
+	srv.Handle(botan.MethodGetMe, func(call botantest.Call) botantest.Response {
+		<-release
+		return botantest.OK(entities.User{Id: botantest.BotUserId, IsBot: true, Username: botantest.BotUsername})
+	})
+	bot := srv.Bot(t, &botan.BotCallbacksContainer{})
+
+	started := make(chan string, 2)
+	router := botan.NewCommandRouter()
+	handler := func(bot *botan.Bot, msg *entities.Message, cmd *botan.Command) error {
+		started <- msg.Text
+		return nil
+	}
+	if errHandle := router.Handle("start", "Start", handler); errHandle != nil {
+		t.Fatal(errHandle)
+	}
+
+	mentionDone := make(chan error, 1)
+	go func() {
+		mentionDone <- router.OnMessage(bot, botantest.NewMessage(-1, 7, "/start@"+botantest.BotUsername))
+	}()
+	srv.WaitForCall(t, botan.MethodGetMe, time.Second)
+
+	// GetMe is still in flight: commands without mention are routed anyway
+	plainDone := make(chan error, 1)
+	go func() {
+		plainDone <- router.OnMessage(bot, botantest.NewMessage(-1, 7, "/start"))
+	}()
+	select {
+	case errPlain := <-plainDone:
+		if errPlain != nil {
+			t.Fatal(errPlain)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("command routing waits for GetMe")
+	}
+
+	releaseGetMe()
+	if errMention := <-mentionDone; errMention != nil {
+		t.Fatal(errMention)
+	}
+	if got := []string{<-started, <-started}; got[0] != "/start" || got[1] != "/start@"+botantest.BotUsername {
+		t.Errorf("handled %q", got)
+	}
+
+	// username is cached, commands for other bots are ignored without calling GetMe again
+	if errOther := router.OnMessage(bot, botantest.NewMessage(-1, 7, "/start@other_bot")); errOther != nil {
+		t.Fatal(errOther)
+	}
+	if calls := srv.Calls(botan.MethodGetMe); len(calls) != 1 {
+		t.Errorf("GetMe called %d times", len(calls))
+	}
+	select {
+	case text := <-started:
+		t.Errorf("command for another bot handled: %s", text)
+	default:
+	}
+}
+
+func TestZeroValueCommandRouter(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+	bot := srv.Bot(t, &botan.BotCallbacksContainer{})
+
+	var router botan.CommandRouter
+	handled := false
+	if errHandle := router.Handle("start", "Start", func(bot *botan.Bot, msg *entities.Message, cmd *botan.Command) error {
+		handled = true
+		return nil
+	}); errHandle != nil {
+		t.Fatal(errHandle)
+	}
+	if errRoute := router.OnMessage(bot, botantest.NewMessage(42, 42, "/start")); errRoute != nil {
+		t.Fatal(errRoute)
+	}
+	if !handled {
+		t.Error("command not handled")
+	}
+}
@@ -61,6 +61,18 @@ const (
 	MethodAddStickerToSet         = "addStickerToSet"
 	MethodSetStickerPositionInSet = "setStickerPositionInSet"
 	MethodDeleteStickerFromSet    = "deleteStickerFromSet"
+	MethodSetWebhook              = "setWebhook"
+	MethodDeleteWebhook           = "deleteWebhook"
+	MethodGetWebhookInfo          = "getWebhookInfo"
+	MethodSendInvoice             = "sendInvoice"
+	MethodAnswerShippingQuery     = "answerShippingQuery"
+	MethodAnswerPreCheckoutQuery  = "answerPreCheckoutQuery"
+	MethodSetPassportDataErrors   = "setPassportDataErrors"
+	MethodSendGame                = "sendGame"
+	MethodSetGameScore            = "setGameScore"
+	MethodGetGameHighScores       = "getGameHighScores"
+	MethodSetMyCommands           = "setMyCommands"
+	MethodGetMyCommands           = "getMyCommands"
 )
 
 // TELEGRAM BOT API FORMATTING OPTIONS
@@ -0,0 +1,264 @@
+// Package conversation implements multi-step dialogs on top of botan: a conversation is a state machine kept
+// per chat and user. Each state has its own handler, which decides what state comes next:
+//
+//	manager := conversation.NewManager(conversation.NewMemoryStorage())
+//	manager.Handle("ask_name", askName)
+//	manager.Handle("ask_phone", askPhone)
+//	callbacks := botan.BotCallbacksContainer{
+//		OnMessage:  router.OnMessage, // e.g. /order command calls manager.Start(key, "ask_name")
+//		Middleware: []botan.Middleware{manager.Middleware()},
+//	}
+//
+// While a conversation is active, messages and callback queries of its chat and user go to the state handler
+// instead of the ordinary callbacks.
+package conversation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isvinogradov/botan"
+	en "github.com/isvinogradov/botan/entities"
+)
+
+const defaultCancelCommand = "cancel"
+
+// Identifies a conversation: the same user may have independent conversations in different chats
+type Key struct {
+	ChatId int `json:"chat_id"`
+	UserId int `json:"user_id"`
+}
+
+func (k Key) String() string {
+	return strconv.Itoa(k.ChatId) + ":" + strconv.Itoa(k.UserId)
+}
+
+// Returns the conversation key of a message or callback query update. Other updates don't belong to conversations.
+func KeyFromUpdate(update *en.Update) (Key, bool) {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		return Key{ChatId: update.Message.Chat.Id, UserId: update.Message.From.Id}, true
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil && update.CallbackQuery.Sender != nil:
+		return Key{ChatId: update.CallbackQuery.Message.Chat.Id, UserId: update.CallbackQuery.Sender.Id}, true
+	}
+	return Key{}, false
+}
+
+// Persistent state of a conversation
+type State struct {
+	Name    string            `json:"name"`    // name of the current state, the one whose handler gets the next update
+	Data    map[string]string `json:"data"`    // values collected during the conversation
+	Updated time.Time         `json:"updated"` // time of the last transition, used for timeouts
+}
+
+// Handles an update of an active conversation. Call c.Transition to move to another state or c.End to finish;
+// otherwise the conversation stays in the current state.
+type Handler func(bot *botan.Bot, update *en.Update, c *Conversation) error
+
+// Conversation being processed by a Handler
+type Conversation struct {
+	Key   Key
+	state *State
+	next  string
+	ended bool
+}
+
+// Name of the current state
+func (c *Conversation) State() string {
+	return c.state.Name
+}
+
+// Moves conversation to the state after the handler returns. The state must have a registered handler, otherwise
+// the update fails and the conversation stays in the current state.
+func (c *Conversation) Transition(state string) {
+	c.next = state
+	c.ended = false
+}
+
+// Finishes conversation after the handler returns; its state is deleted from storage
+func (c *Conversation) End() {
+	c.ended = true
+}
+
+// Returns the value saved with Set
+func (c *Conversation) Get(name string) string {
+	return c.state.Data[name]
+}
+
+// Saves value in conversation state
+func (c *Conversation) Set(name string, value string) {
+	if c.state.Data == nil {
+		c.state.Data = make(map[string]string)
+	}
+	c.state.Data[name] = value
+}
+
+// Keeps conversations in Storage and routes their updates to state handlers
+type Manager struct {
+	Timeout        time.Duration                                          // Optional. Conversations idle for longer are dropped when their next update arrives; never by default
+	CancelCommands []string                                               // Commands which abort any conversation; /cancel by default
+	OnCancel       func(bot *botan.Bot, msg *en.Message, key Key) error   // Optional. Called after a conversation was cancelled with a command, e.g. to confirm it to the user
+	OnTimeout      func(bot *botan.Bot, update *en.Update, key Key) error // Optional. Called when a conversation timed out; the update is processed by ordinary callbacks afterwards
+
+	storage  Storage
+	handlers map[string]Handler
+
+	mu          sync.Mutex
+	botUsername string // cached result of GetMe, for commands with @botname suffix
+}
+
+func NewManager(storage Storage) *Manager {
+	return &Manager{
+		CancelCommands: []string{defaultCancelCommand},
+		storage:        storage,
+		handlers:       make(map[string]Handler),
+	}
+}
+
+// Registers handler for the state. Handlers must be registered before the bot starts receiving updates.
+func (m *Manager) Handle(state string, handler Handler) {
+	m.handlers[state] = handler
+}
+
+// Starts a conversation in the given state, replacing the active one if any. The next update of the chat
+// and user goes to the state handler.
+func (m *Manager) Start(key Key, state string) error {
+	if _, found := m.handlers[state]; !found {
+		return fmt.Errorf("conversation: no handler for state %s", state)
+	}
+	return m.storage.Set(key, &State{Name: state, Data: make(map[string]string), Updated: time.Now()})
+}
+
+// Aborts the conversation if it's active
+func (m *Manager) Stop(key Key) error {
+	return m.storage.Delete(key)
+}
+
+// Returns state of the active conversation or nil
+func (m *Manager) Current(key Key) (*State, error) {
+	return m.storage.Get(key)
+}
+
+// Middleware passing updates of active conversations to state handlers; other updates go further
+// to the ordinary callbacks.
+func (m *Manager) Middleware() botan.Middleware {
+	return func(next botan.Handler) botan.Handler {
+		return func(bot *botan.Bot, update *en.Update) error {
+			key, ok := KeyFromUpdate(update)
+			if !ok {
+				return next(bot, update)
+			}
+			handled, errHandle := m.handleUpdate(bot, update, key)
+			if errHandle != nil || handled {
+				return errHandle
+			}
+			return next(bot, update)
+		}
+	}
+}
+
+// Returns false if the update doesn't belong to an active conversation
+func (m *Manager) handleUpdate(bot *botan.Bot, update *en.Update, key Key) (bool, error) {
+	state, errGet := m.storage.Get(key)
+	if errGet != nil {
+		return false, errGet
+	}
+	if state == nil {
+		return false, nil
+	}
+
+	if m.Timeout > 0 && time.Since(state.Updated) > m.Timeout {
+		if errDelete := m.storage.Delete(key); errDelete != nil {
+			return false, errDelete
+		}
+		if m.OnTimeout != nil {
+			if errTimeout := m.OnTimeout(bot, update, key); errTimeout != nil {
+				return false, errTimeout
+			}
+		}
+		return false, nil
+	}
+
+	isCancel, errCancel := m.isCancelCommand(bot, update.Message)
+	if errCancel != nil {
+		return true, errCancel
+	}
+	if isCancel {
+		if errDelete := m.storage.Delete(key); errDelete != nil {
+			return true, errDelete
+		}
+		if m.OnCancel != nil {
+			return true, m.OnCancel(bot, update.Message, key)
+		}
+		return true, nil
+	}
+
+	handler, found := m.handlers[state.Name]
+	if !found {
+		// state was saved by another version of the bot, the conversation can't be continued
+		return false, errors.Join(
+			fmt.Errorf("conversation: no handler for state %s of %s", state.Name, key),
+			m.storage.Delete(key),
+		)
+	}
+
+	c := Conversation{Key: key, state: state, next: state.Name}
+	if errHandler := handler(bot, update, &c); errHandler != nil {
+		return true, errHandler // state is not changed, so the step can be repeated
+	}
+	if c.ended {
+		return true, m.storage.Delete(key)
+	}
+	if _, found := m.handlers[c.next]; !found {
+		return true, fmt.Errorf("conversation: no handler for state %s, %s stays in state %s", c.next, key, state.Name)
+	}
+	state.Name = c.next
+	state.Updated = time.Now()
+	return true, m.storage.Set(key, state)
+}
+
+// Commands addressed to other bots (/cancel@otherbot) don't cancel conversations
+func (m *Manager) isCancelCommand(bot *botan.Bot, msg *en.Message) (bool, error) {
+	cmd, mention, ok := botan.ParseCommand(msg)
+	if !ok {
+		return false, nil
+	}
+	isCancel := false
+	for _, cancelCommand := range m.CancelCommands {
+		if cmd.Name == cancelCommand {
+			isCancel = true
+			break
+		}
+	}
+	if !isCancel || mention == "" {
+		return isCancel, nil
+	}
+	username, errMe := m.username(bot)
+	if errMe != nil {
+		return false, errMe
+	}
+	return strings.EqualFold(mention, username), nil
+}
+
+// Returns the bot's username; GetMe is called until it succeeds once, without holding the lock
+func (m *Manager) username(bot *botan.Bot) (string, error) {
+	m.mu.Lock()
+	username := m.botUsername
+	m.mu.Unlock()
+	if username != "" {
+		return username, nil
+	}
+
+	me, errMe := bot.GetMe()
+	if errMe != nil {
+		return "", errMe
+	}
+	m.mu.Lock()
+	m.botUsername = me.Username
+	m.mu.Unlock()
+	return me.Username, nil
+}
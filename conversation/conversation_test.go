@@ -0,0 +1,139 @@
+package conversation_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+	"github.com/isvinogradov/botan/conversation"
+	en "github.com/isvinogradov/botan/entities"
+)
+
+const (
+	chatId = 42
+	userId = 7
+)
+
+var key = conversation.Key{ChatId: chatId, UserId: userId}
+
+func newManager(t *testing.T) (*conversation.Manager, botan.Handler) {
+	t.Helper()
+	manager := conversation.NewManager(conversation.NewMemoryStorage())
+	manager.Handle("ask_name", func(bot *botan.Bot, update *en.Update, c *conversation.Conversation) error {
+		c.Set("name", update.Message.Text)
+		c.Transition(update.Message.Text) // state named by the user, which may not exist
+		return nil
+	})
+	manager.Handle("done", func(bot *botan.Bot, update *en.Update, c *conversation.Conversation) error {
+		c.End()
+		return nil
+	})
+	if errStart := manager.Start(key, "ask_name"); errStart != nil {
+		t.Fatal(errStart)
+	}
+	notInConversation := func(bot *botan.Bot, update *en.Update) error {
+		t.Errorf("update %q passed to ordinary callbacks", update.Message.Text)
+		return nil
+	}
+	return manager, manager.Middleware()(notInConversation)
+}
+
+func send(bot *botan.Bot, handler botan.Handler, text string) error {
+	return handler(bot, &en.Update{Message: botantest.NewMessage(chatId, userId, text)})
+}
+
+func currentState(t *testing.T, manager *conversation.Manager) string {
+	t.Helper()
+	state, errState := manager.Current(key)
+	if errState != nil {
+		t.Fatal(errState)
+	}
+	if state == nil {
+		return ""
+	}
+	return state.Name
+}
+
+func TestTransitionToUnknownState(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+	bot := srv.Bot(t, &botan.BotCallbacksContainer{})
+	manager, handler := newManager(t)
+
+	if errSend := send(bot, handler, "missing"); errSend == nil {
+		t.Error("transition to a state without handler succeeded")
+	}
+	if state := currentState(t, manager); state != "ask_name" {
+		t.Errorf("state %q after failed transition, want ask_name", state)
+	}
+
+	if errSend := send(bot, handler, "done"); errSend != nil {
+		t.Fatal(errSend)
+	}
+	if state := currentState(t, manager); state != "done" {
+		t.Errorf("state %q, want done", state)
+	}
+}
+
+func TestCancelCommandMention(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+	bot := srv.Bot(t, &botan.BotCallbacksContainer{})
+
+	tests := []struct {
+		text       string
+		wantActive bool
+	}{
+		{"/cancel", false},
+		{"/cancel@" + botantest.BotUsername, false},
+		{"/cancel@other_bot", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			manager, handler := newManager(t)
+			manager.Handle("/cancel@other_bot", func(bot *botan.Bot, update *en.Update, c *conversation.Conversation) error {
+				return nil
+			})
+			if errSend := send(bot, handler, tt.text); errSend != nil {
+				t.Fatal(errSend)
+			}
+			if active := currentState(t, manager) != ""; active != tt.wantActive {
+				t.Errorf("conversation active %v, want %v", active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestFileStorageKeepsMemoryOnFailedSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "conversations.json")
+	storage, errOpen := conversation.NewFileStorage(path)
+	if errOpen != nil {
+		t.Fatal(errOpen)
+	}
+	saved, failed := conversation.Key{ChatId: 1, UserId: 1}, conversation.Key{ChatId: 2, UserId: 2}
+	if errSet := storage.Set(saved, &conversation.State{Name: "name"}); errSet != nil {
+		t.Fatal(errSet)
+	}
+
+	// a non-empty directory in place of the file makes rename fail
+	if errRemove := os.Remove(path); errRemove != nil {
+		t.Fatal(errRemove)
+	}
+	if errMkdir := os.MkdirAll(filepath.Join(path, "blocker"), 0o700); errMkdir != nil {
+		t.Fatal(errMkdir)
+	}
+	if errSet := storage.Set(failed, &conversation.State{Name: "name"}); errSet == nil {
+		t.Fatal("Set succeeded without writing the file")
+	}
+	if errDelete := storage.Delete(saved); errDelete == nil {
+		t.Fatal("Delete succeeded without writing the file")
+	}
+	if state, _ := storage.Get(failed); state != nil {
+		t.Errorf("state which wasn't saved is in memory: %+v", state)
+	}
+	if state, _ := storage.Get(saved); state == nil {
+		t.Error("state which wasn't deleted from the file is gone from memory")
+	}
+}
@@ -0,0 +1,157 @@
+package conversation
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Keeps conversation states. Implement it on top of a database to share conversations between bot processes.
+type Storage interface {
+	Get(key Key) (*State, error) // nil state if there's no active conversation
+	Set(key Key, state *State) error
+	Delete(key Key) error
+}
+
+// In-memory Storage; conversations are lost on restart
+type MemoryStorage struct {
+	mu     sync.Mutex
+	states map[Key]State
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{states: make(map[Key]State)}
+}
+
+func (ms *MemoryStorage) Get(key Key) (*State, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	state, found := ms.states[key]
+	if !found {
+		return nil, nil
+	}
+	return copyState(&state), nil
+}
+
+func (ms *MemoryStorage) Set(key Key, state *State) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.states[key] = *copyState(state)
+	return nil
+}
+
+func (ms *MemoryStorage) Delete(key Key) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.states, key)
+	return nil
+}
+
+// stored states must not share Data map with the ones being modified by handlers
+func copyState(state *State) *State {
+	stateCopy := *state
+	stateCopy.Data = make(map[string]string, len(state.Data))
+	for k, v := range state.Data {
+		stateCopy.Data[k] = v
+	}
+	return &stateCopy
+}
+
+// Storage keeping all conversations in a single JSON file, so they survive restarts. The file is rewritten
+// on every change, which is fine for bots with moderate number of simultaneous conversations.
+type FileStorage struct {
+	path   string
+	memory *MemoryStorage
+}
+
+// Opens storage at path, loading conversations saved there before. The file is created on the first change.
+func NewFileStorage(path string) (*FileStorage, error) {
+	fs := FileStorage{path: path, memory: NewMemoryStorage()}
+	content, errRead := os.ReadFile(path)
+	if errors.Is(errRead, os.ErrNotExist) {
+		return &fs, nil
+	}
+	if errRead != nil {
+		return nil, errRead
+	}
+
+	var saved []savedState
+	if errUnmarshal := json.Unmarshal(content, &saved); errUnmarshal != nil {
+		return nil, errUnmarshal
+	}
+	for i := range saved {
+		fs.memory.states[saved[i].Key] = saved[i].State
+	}
+	return &fs, nil
+}
+
+type savedState struct {
+	Key   Key   `json:"key"`
+	State State `json:"state"`
+}
+
+func (fs *FileStorage) Get(key Key) (*State, error) {
+	return fs.memory.Get(key)
+}
+
+func (fs *FileStorage) Set(key Key, state *State) error {
+	fs.memory.mu.Lock()
+	defer fs.memory.mu.Unlock()
+	states := fs.copyStates()
+	states[key] = *copyState(state)
+	return fs.save(states)
+}
+
+func (fs *FileStorage) Delete(key Key) error {
+	fs.memory.mu.Lock()
+	defer fs.memory.mu.Unlock()
+	if _, found := fs.memory.states[key]; !found {
+		return nil
+	}
+	states := fs.copyStates()
+	delete(states, key)
+	return fs.save(states)
+}
+
+// Returns a copy of states in memory to be modified and saved. Must be called with the lock held.
+func (fs *FileStorage) copyStates() map[Key]State {
+	states := make(map[Key]State, len(fs.memory.states)+1)
+	for key, state := range fs.memory.states {
+		states[key] = state
+	}
+	return states
+}
+
+// Writes states to a temporary file and renames it, so the file is never left half-written. States in memory
+// are replaced only when the file is written, so memory and file stay the same if saving fails.
+// Must be called with the lock held.
+func (fs *FileStorage) save(states map[Key]State) error {
+	saved := make([]savedState, 0, len(states))
+	for key, state := range states {
+		saved = append(saved, savedState{Key: key, State: state})
+	}
+	content, errMarshal := json.Marshal(saved)
+	if errMarshal != nil {
+		return errMarshal
+	}
+
+	tmpFile, errCreate := os.CreateTemp(filepath.Dir(fs.path), ".botan-conversations-*")
+	if errCreate != nil {
+		return errCreate
+	}
+	_, errWrite := tmpFile.Write(content)
+	if errClose := tmpFile.Close(); errWrite == nil {
+		errWrite = errClose
+	}
+	if errWrite == nil {
+		errWrite = os.Rename(tmpFile.Name(), fs.path)
+	}
+	if errWrite != nil {
+		os.Remove(tmpFile.Name())
+		return errWrite
+	}
+	fs.memory.states = states
+	return nil
+}
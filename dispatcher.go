@@ -0,0 +1,136 @@
+package botan
+
+import (
+	"context"
+	"sync"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+const (
+	defaultWorkers        = 1
+	dispatcherQueueLength = 100 // getUpdates returns at most 100 updates at once
+)
+
+// Distributes updates between a pool of workers. Updates with the same ordering key (chat or user ID) always go
+// to the same worker, so they are processed one after another in the order they were received.
+// Offset is committed via OnSetNewOffset only when all updates received before it are processed.
+type dispatcher struct {
+	bot    *Bot
+	queues []chan *entities.Update
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex
+	pending   []int        // IDs of dispatched updates in order of arrival
+	done      map[int]bool // IDs of processed updates which can't be committed yet
+	committed int          // last offset passed to OnSetNewOffset
+}
+
+func newDispatcher(bot *Bot, workers int, offset int) *dispatcher {
+	d := dispatcher{
+		bot:       bot,
+		queues:    make([]chan *entities.Update, workers),
+		done:      make(map[int]bool),
+		committed: offset,
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan *entities.Update, dispatcherQueueLength)
+		d.wg.Add(1)
+		go d.work(d.queues[i])
+	}
+	return &d
+}
+
+func (d *dispatcher) work(queue <-chan *entities.Update) {
+	defer d.wg.Done()
+	for update := range queue {
+		d.bot.processUpdate(update)
+		d.complete(update.UpdateId)
+	}
+}
+
+// Schedules update for processing. Blocks while the respective worker queue is full.
+// Returns false if ctx was cancelled before the update was scheduled.
+func (d *dispatcher) dispatch(ctx context.Context, update *entities.Update) bool {
+	queue := d.queues[updateOrderingKey(update)%len(d.queues)]
+
+	d.mu.Lock()
+	d.pending = append(d.pending, update.UpdateId)
+	d.mu.Unlock()
+
+	select {
+	case queue <- update:
+		return true
+	case <-ctx.Done():
+		// update was not scheduled, so it can't be processed yet; it's always the last pending one
+		d.mu.Lock()
+		d.pending = d.pending[:len(d.pending)-1]
+		d.mu.Unlock()
+		return false
+	}
+}
+
+// Marks update as processed and commits the longest processed prefix of pending updates.
+func (d *dispatcher) complete(updateId int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.done[updateId] = true
+	newOffset := d.committed
+	for len(d.pending) > 0 && d.done[d.pending[0]] {
+		newOffset = d.pending[0]
+		delete(d.done, d.pending[0])
+		d.pending = d.pending[1:]
+	}
+	if newOffset != d.committed {
+		d.committed = newOffset
+		// store new offset to external storage if provided
+		d.bot.callbacks.OnSetNewOffset(newOffset)
+	}
+}
+
+// Waits for all scheduled updates to be processed and returns the last committed offset.
+func (d *dispatcher) stop() int {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.committed
+}
+
+// Returns non-negative key used to keep updates from the same chat (or from the same user if there's no chat)
+// in order. Updates which have neither are spread between workers by update ID.
+func updateOrderingKey(update *entities.Update) int {
+	var key int
+	switch {
+	case update.Message != nil:
+		key = update.Message.Chat.Id
+	case update.EditedMessage != nil:
+		key = update.EditedMessage.Chat.Id
+	case update.ChannelPost != nil:
+		key = update.ChannelPost.Chat.Id
+	case update.EditedChannelPost != nil:
+		key = update.EditedChannelPost.Chat.Id
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		key = update.CallbackQuery.Message.Chat.Id
+	case update.CallbackQuery != nil:
+		key = update.CallbackQuery.Sender.Id
+	case update.InlineQuery != nil:
+		key = update.InlineQuery.Sender.Id
+	case update.ChosenInlineResult != nil:
+		key = update.ChosenInlineResult.Sender.Id
+	case update.ShippingQuery != nil:
+		key = update.ShippingQuery.From.Id
+	case update.PreCheckoutQuery != nil:
+		key = update.PreCheckoutQuery.From.Id
+	default:
+		key = update.UpdateId
+	}
+	if key < 0 {
+		key = -key // group and channel IDs are negative
+	}
+	return key
+}
@@ -0,0 +1,144 @@
+package botan
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+func TestDispatcherCommitsProcessedPrefix(t *testing.T) {
+	type update struct {
+		id   int
+		chat int
+	}
+	tests := []struct {
+		name        string
+		workers     int
+		updates     []update
+		release     []int // update IDs in the order their handlers are allowed to finish
+		waitEach    bool  // wait for each released update to complete before releasing the next one
+		wantOffsets []int // OnSetNewOffset calls; checked only if waitEach is set
+	}{
+		{
+			name:        "in order",
+			workers:     2,
+			updates:     []update{{1, 1}, {2, 2}, {3, 1}},
+			release:     []int{1, 2, 3},
+			waitEach:    true,
+			wantOffsets: []int{1, 2, 3},
+		},
+		{
+			name:        "later update of another chat completes first",
+			workers:     2,
+			updates:     []update{{1, 1}, {2, 2}, {3, 1}},
+			release:     []int{2, 1, 3},
+			waitEach:    true,
+			wantOffsets: []int{2, 3},
+		},
+		{
+			name:        "whole tail waits for the first update",
+			workers:     3,
+			updates:     []update{{10, 1}, {11, 2}, {12, 3}, {13, 2}},
+			release:     []int{11, 12, 13, 10},
+			waitEach:    true,
+			wantOffsets: []int{13},
+		},
+		{
+			name:     "same chat keeps order when released in reverse",
+			workers:  2,
+			updates:  []update{{1, 1}, {2, 2}, {3, 1}, {4, 2}, {5, 1}},
+			release:  []int{5, 4, 3, 2, 1},
+			waitEach: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var offsets []int
+			processed := make(map[int][]int) // chat ID -> update IDs in order of processing
+			gates := make(map[int]chan struct{})
+			for _, u := range tt.updates {
+				gates[u.id] = make(chan struct{})
+			}
+
+			bot, errBot := NewBot(&Config{Token: "1:test"}, &BotCallbacksContainer{
+				OnMessage: func(bot *Bot, msg *entities.Message) error {
+					<-gates[msg.MessageId]
+					mu.Lock()
+					processed[msg.Chat.Id] = append(processed[msg.Chat.Id], msg.MessageId)
+					mu.Unlock()
+					return nil
+				},
+				OnSetNewOffset: func(newUpdateId int) {
+					mu.Lock()
+					offsets = append(offsets, newUpdateId)
+					mu.Unlock()
+				},
+				OnError: func(err error) { t.Error(err) },
+			})
+			if errBot != nil {
+				t.Fatal(errBot)
+			}
+
+			d := newDispatcher(bot, tt.workers, 0)
+			for _, u := range tt.updates {
+				msg := entities.Message{MessageId: u.id, Chat: &entities.Chat{Id: u.chat}}
+				if !d.dispatch(context.Background(), &entities.Update{UpdateId: u.id, Message: &msg}) {
+					t.Fatalf("update %d not dispatched", u.id)
+				}
+			}
+			for _, id := range tt.release {
+				close(gates[id])
+				if tt.waitEach {
+					waitCompleted(t, d, id)
+				}
+			}
+			committed := d.stop()
+
+			last := tt.updates[len(tt.updates)-1].id
+			if committed != last {
+				t.Errorf("committed offset %d, want %d", committed, last)
+			}
+			if tt.waitEach && !reflect.DeepEqual(offsets, tt.wantOffsets) {
+				t.Errorf("OnSetNewOffset calls %v, want %v", offsets, tt.wantOffsets)
+			}
+			for i := 1; i < len(offsets); i++ {
+				if offsets[i] <= offsets[i-1] {
+					t.Errorf("offsets are not increasing: %v", offsets)
+				}
+			}
+			for _, u := range tt.updates {
+				var want []int
+				for _, other := range tt.updates {
+					if other.chat == u.chat {
+						want = append(want, other.id)
+					}
+				}
+				if got := processed[u.chat]; !reflect.DeepEqual(got, want) {
+					t.Errorf("chat %d processed %v, want %v", u.chat, got, want)
+				}
+			}
+		})
+	}
+}
+
+// Waits until dispatcher has registered completion of the update
+func waitCompleted(t *testing.T, d *dispatcher, updateId int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		d.mu.Lock()
+		completed := d.done[updateId] || d.committed >= updateId
+		d.mu.Unlock()
+		if completed {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("update %d not completed", updateId)
+}
@@ -0,0 +1,98 @@
+package botan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// DownloadFile gets file info with GetFile and streams contents of the file to w. For the moment, bots can download
+// files of up to 20MB in size (no limit with a local Bot API server). Returns an error if the number of bytes
+// received differs from the file size reported by Telegram. With Config.LocalMode the file is read from the local
+// file system.
+func (bot *Bot) DownloadFile(fileId string, w io.Writer) error {
+	file, errGetFile := bot.GetFile(&GetFileRequest{FileId: fileId})
+	if errGetFile != nil {
+		return errGetFile
+	}
+	if file.FilePath == "" {
+		return fmt.Errorf("file %s is not available for download", fileId)
+	}
+	if bot.config.LocalMode && filepath.IsAbs(file.FilePath) {
+		return copyLocalFile(file.FilePath, w, int64(file.FileSize))
+	}
+	return bot.requestGate.download(bot.context(), bot.urls.fileDownload+file.FilePath, w, int64(file.FileSize))
+}
+
+// Copies file saved by local Bot API server to w; expectedSize is checked if it's greater than 0
+func copyLocalFile(path string, w io.Writer, expectedSize int64) error {
+	file, errOpen := os.Open(path)
+	if errOpen != nil {
+		return errOpen
+	}
+	defer file.Close()
+	written, errCopy := io.Copy(w, file)
+	if errCopy != nil {
+		return errCopy
+	}
+	if expectedSize > 0 && written != expectedSize {
+		return fmt.Errorf("file %s: read %d bytes, expected %d", path, written, expectedSize)
+	}
+	return nil
+}
+
+// DownloadFileTo downloads file to the local path. The file is written to a temporary file in the same directory
+// first and is renamed only when download succeeds, so path never contains a partially downloaded file.
+func (bot *Bot) DownloadFileTo(fileId string, path string) error {
+	tmpFile, errCreate := os.CreateTemp(filepath.Dir(path), ".botan-download-*")
+	if errCreate != nil {
+		return errCreate
+	}
+	errDownload := bot.DownloadFile(fileId, tmpFile)
+	if errClose := tmpFile.Close(); errDownload == nil {
+		errDownload = errClose
+	}
+	if errDownload == nil {
+		errDownload = os.Rename(tmpFile.Name(), path)
+	}
+	if errDownload != nil {
+		_ = os.Remove(tmpFile.Name())
+		return errDownload
+	}
+	return nil
+}
+
+// Streams response body to w; expectedSize is checked if it's greater than 0
+func (rg *requestGate) download(ctx context.Context, url string, w io.Writer, expectedSize int64) error {
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if errReq != nil {
+		return errReq
+	}
+	r, errGet := rg.uploadClient.Do(req) // file transfers take much longer than ordinary requests
+	if errGet != nil {
+		return redactUrlError(errGet)
+	}
+	defer rg.closeBody(r)
+
+	if r.StatusCode != http.StatusOK {
+		errApi := decodeApiResponse(url, r, nil)
+		var apiErr *APIError
+		if errors.As(errApi, &apiErr) {
+			apiErr.Method = "downloadFile" // URL ends with the file path, not with a method name
+		}
+		return errApi
+	}
+
+	written, errCopy := io.Copy(w, r.Body)
+	if errCopy != nil {
+		return redactUrlError(errCopy)
+	}
+	if expectedSize > 0 && written != expectedSize {
+		return fmt.Errorf("downloaded %d bytes of file, expected %d", written, expectedSize)
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package entities
 
+import "encoding/json"
+
 // The response contains a JSON object, which always has a Boolean field ‘ok’ and may have an optional
 // String field ‘description’ with a human-readable description of the result.
 type ApiResponse struct {
 	OK          bool               `json:"ok"`                    // If ‘ok’ equals true, the request was successful and the result of the query can be found in the ‘result’ field. In case of an unsuccessful request, ‘ok’ equals false and the error is explained in the ‘description’.
-	Result      interface{}        `json:"result"`                // If ‘ok’ equals true, the request was successful and the result of the query can be found in the ‘result’ field.
+	Result      json.RawMessage    `json:"result"`                // If ‘ok’ equals true, the request was successful and the result of the query can be found in the ‘result’ field.
 	Description string             `json:"description,omitempty"` // Optional. In case of an unsuccessful request, ‘ok’ equals false and the error is explained in the ‘description’.
 	ErrorCode   int                `json:"error_code,omitempty"`  // Optional. An Integer ‘error_code’ field is also returned, but its contents are subject to change in the future.
 	RespParams  ResponseParameters `json:"parameters,omitempty"`  // Optional. Some errors may also have an optional field ‘parameters’ of the type ResponseParameters, which can help to automatically handle the error.
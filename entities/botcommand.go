@@ -0,0 +1,7 @@
+package entities
+
+// This object represents a bot command.
+type BotCommand struct {
+	Command     string `json:"command"`     // Text of the command, 1-32 characters. Can contain only lowercase English letters, digits and underscores.
+	Description string `json:"description"` // Description of the command, 3-256 characters.
+}
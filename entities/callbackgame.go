@@ -0,0 +1,4 @@
+package entities
+
+// A placeholder, currently holds no information. Use BotFather to set up your game.
+type CallbackGame struct{}
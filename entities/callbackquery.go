@@ -5,10 +5,11 @@ package entities
 // was attached to a message sent via the bot (in inline mode), the field inline_message_id will be present.
 // Exactly one of the fields data or game_short_name will be present.
 type CallbackQuery struct {
-	Id           string   `json:"id"`                          // Unique identifier for this query
-	Sender       *User    `json:"from"`                        // Sender
-	ChatInstance string   `json:"chat_instance"`               // Global identifier, uniquely corresponding to the chat to which the message with the callback button was sent.
-	Message      *Message `json:"message,omitempty"`           // Optional. Message with the callback button that originated the query
-	CallbackData string   `json:"data,omitempty"`              // Optional. Data associated with the callback button. Bad client can send arbitrary data in this field.
-	InlineMsgId  string   `json:"inline_message_id,omitempty"` // Optional. Identifier of the message sent via the bot in inline mode, that originated the query.
+	Id            string   `json:"id"`                          // Unique identifier for this query
+	Sender        *User    `json:"from"`                        // Sender
+	ChatInstance  string   `json:"chat_instance"`               // Global identifier, uniquely corresponding to the chat to which the message with the callback button was sent.
+	Message       *Message `json:"message,omitempty"`           // Optional. Message with the callback button that originated the query
+	CallbackData  string   `json:"data,omitempty"`              // Optional. Data associated with the callback button. Bad client can send arbitrary data in this field.
+	InlineMsgId   string   `json:"inline_message_id,omitempty"` // Optional. Identifier of the message sent via the bot in inline mode, that originated the query.
+	GameShortName string   `json:"game_short_name,omitempty"`   // Optional. Short name of a Game to be returned, serves as the unique identifier for the game
 }
@@ -1,9 +1,6 @@
 package entities
 
-import (
-	"encoding/json"
-	"errors"
-)
+import "fmt"
 
 // This object represents a chat.
 type Chat struct {
@@ -35,8 +32,14 @@ type ChatId struct {
 
 func (cid *ChatId) MarshalJSON() ([]byte, error) {
 	switch t := cid.ExactType.(type) {
-	case int, string:
-		return json.Marshal(t)
+	case int:
+		return marshalExactType("ChatId", t)
+	case string:
+		if t == "" {
+			return nil, fmt.Errorf("%w: ChatId holds empty string", ErrInvalidExactType)
+		}
+		return marshalExactType("ChatId", t)
+	default:
+		return nil, invalidExactType("ChatId", t)
 	}
-	panic(errors.New("invalid ChatId value (must be int or string)"))
 }
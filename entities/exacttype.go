@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidExactType is returned (wrapped) by Bot methods when a wrapper like ReplyMarkup or ChatId holds
+// a value of unsupported type or a nil pointer. Such requests are never sent.
+var ErrInvalidExactType = errors.New("invalid ExactType value")
+
+// Marshals value held by wrapper; typed nil pointers are rejected, otherwise they'd be sent as null
+func marshalExactType(wrapper string, value interface{}) ([]byte, error) {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, fmt.Errorf("%w: %s holds nil %T", ErrInvalidExactType, wrapper, value)
+	}
+	return json.Marshal(value)
+}
+
+func invalidExactType(wrapper string, value interface{}) error {
+	return fmt.Errorf("%w: %s can't hold %T", ErrInvalidExactType, wrapper, value)
+}
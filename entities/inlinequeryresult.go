@@ -1,10 +1,5 @@
 package entities
 
-import (
-	"encoding/json"
-	"errors"
-)
-
 // This object represents one result of an inline query.
 // Telegram clients currently support results of the following 20 types:
 // - InlineQueryResultCachedAudio
@@ -75,9 +70,10 @@ func (iqr *InlineQueryResult) MarshalJSON() ([]byte, error) {
 		*InlineQueryResultVideo,
 		InlineQueryResultVoice,
 		*InlineQueryResultVoice:
-		return json.Marshal(t)
+		return marshalExactType("InlineQueryResult", t)
+	default:
+		return nil, invalidExactType("InlineQueryResult", t)
 	}
-	panic(errors.New("invalid InlineQueryResult value"))
 }
 
 // Represents a link to an article or web page.
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+)
+
+// This object represents the contents of a file to be sent. Set exactly one of the fields:
+// - FileId to resend a file that exists on the Telegram servers (recommended)
+// - Url for Telegram to get a file from the Internet
+// - Path to upload a local file
+// - Reader to upload a file from arbitrary source; Name must be set too
+// Files set with Path or Reader are uploaded using multipart/form-data. They are streamed, never loaded to memory
+// as a whole. Note that a Reader can be read only once, so requests with it are never retried.
+type InputFile struct {
+	FileId string    // file_id of a file that exists on the Telegram servers
+	Url    string    // HTTP URL of a file
+	Path   string    // local path to the file to be uploaded
+	Reader io.Reader // contents of the file to be uploaded
+	Name   string    // file name for upload; defaults to the base name of Path
+}
+
+// Creates InputFile to resend a file that exists on the Telegram servers
+func InputFileFromId(fileId string) *InputFile {
+	return &InputFile{FileId: fileId}
+}
+
+// Creates InputFile for Telegram to get a file from the Internet
+func InputFileFromUrl(url string) *InputFile {
+	return &InputFile{Url: url}
+}
+
+// Creates InputFile to upload a local file
+func InputFileFromPath(path string) *InputFile {
+	return &InputFile{Path: path}
+}
+
+// Creates InputFile to upload contents of r as a file with the given name
+func InputFileFromReader(name string, r io.Reader) *InputFile {
+	return &InputFile{Name: name, Reader: r}
+}
+
+// True if the file should be uploaded with multipart/form-data
+func (f *InputFile) IsUpload() bool {
+	return f.Path != "" || f.Reader != nil
+}
+
+// File name for multipart/form-data
+func (f *InputFile) FileName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	if f.Path != "" {
+		return filepath.Base(f.Path)
+	}
+	return "file"
+}
+
+// Custom Marshaler allows to send file_id or URL as a plain string. Uploads nested in JSON fields are replaced
+// with "attach://<name>" references by the bot when the request is sent.
+func (f *InputFile) MarshalJSON() ([]byte, error) {
+	switch {
+	case f.IsUpload():
+		return nil, errors.New("InputFile upload can't be serialized to JSON")
+	case f.FileId != "":
+		return json.Marshal(f.FileId)
+	}
+	return json.Marshal(f.Url)
+}
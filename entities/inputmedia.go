@@ -0,0 +1,85 @@
+package entities
+
+// This object represents the content of a media message to be sent. It should be one of
+// - InputMediaAnimation
+// - InputMediaDocument
+// - InputMediaAudio
+// - InputMediaPhoto
+// - InputMediaVideo
+type InputMedia struct {
+	ExactType interface{} // put one of the appropriate types above here
+}
+
+// Custom Marshaler allows to substitute InputMedia with exact type and validate it
+func (im *InputMedia) MarshalJSON() ([]byte, error) {
+	switch t := im.ExactType.(type) {
+	case
+		InputMediaAnimation,
+		*InputMediaAnimation,
+		InputMediaDocument,
+		*InputMediaDocument,
+		InputMediaAudio,
+		*InputMediaAudio,
+		InputMediaPhoto,
+		*InputMediaPhoto,
+		InputMediaVideo,
+		*InputMediaVideo:
+		return marshalExactType("InputMedia", t)
+	default:
+		return nil, invalidExactType("InputMedia", t)
+	}
+}
+
+// Represents a photo to be sent.
+type InputMediaPhoto struct {
+	Type      string     `json:"type"`                 // Type of the result, must be photo
+	Media     *InputFile `json:"media"`                // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or upload a new one
+	Caption   string     `json:"caption,omitempty"`    // Optional. Caption of the photo to be sent, 0-1024 characters
+	ParseMode string     `json:"parse_mode,omitempty"` // Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
+}
+
+// Represents a video to be sent.
+type InputMediaVideo struct {
+	Type              string     `json:"type"`                         // Type of the result, must be video
+	Media             *InputFile `json:"media"`                        // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or upload a new one
+	Thumb             *InputFile `json:"thumb,omitempty"`              // Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data.
+	Caption           string     `json:"caption,omitempty"`            // Optional. Caption of the video to be sent, 0-1024 characters
+	ParseMode         string     `json:"parse_mode,omitempty"`         // Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
+	Width             int        `json:"width,omitempty"`              // Optional. Video width
+	Height            int        `json:"height,omitempty"`             // Optional. Video height
+	Duration          int        `json:"duration,omitempty"`           // Optional. Video duration
+	SupportsStreaming bool       `json:"supports_streaming,omitempty"` // Optional. Pass True, if the uploaded video is suitable for streaming
+}
+
+// Represents an animation file (GIF or H.264/MPEG-4 AVC video without sound) to be sent.
+type InputMediaAnimation struct {
+	Type      string     `json:"type"`                 // Type of the result, must be animation
+	Media     *InputFile `json:"media"`                // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or upload a new one
+	Thumb     *InputFile `json:"thumb,omitempty"`      // Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data.
+	Caption   string     `json:"caption,omitempty"`    // Optional. Caption of the animation to be sent, 0-1024 characters
+	ParseMode string     `json:"parse_mode,omitempty"` // Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
+	Width     int        `json:"width,omitempty"`      // Optional. Animation width
+	Height    int        `json:"height,omitempty"`     // Optional. Animation height
+	Duration  int        `json:"duration,omitempty"`   // Optional. Animation duration
+}
+
+// Represents an audio file to be treated as music to be sent.
+type InputMediaAudio struct {
+	Type      string     `json:"type"`                 // Type of the result, must be audio
+	Media     *InputFile `json:"media"`                // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or upload a new one
+	Thumb     *InputFile `json:"thumb,omitempty"`      // Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data.
+	Caption   string     `json:"caption,omitempty"`    // Optional. Caption of the audio to be sent, 0-1024 characters
+	ParseMode string     `json:"parse_mode,omitempty"` // Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
+	Duration  int        `json:"duration,omitempty"`   // Optional. Duration of the audio in seconds
+	Performer string     `json:"performer,omitempty"`  // Optional. Performer of the audio
+	Title     string     `json:"title,omitempty"`      // Optional. Title of the audio
+}
+
+// Represents a general file to be sent.
+type InputMediaDocument struct {
+	Type      string     `json:"type"`                 // Type of the result, must be document
+	Media     *InputFile `json:"media"`                // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or upload a new one
+	Thumb     *InputFile `json:"thumb,omitempty"`      // Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data.
+	Caption   string     `json:"caption,omitempty"`    // Optional. Caption of the document to be sent, 0-1024 characters
+	ParseMode string     `json:"parse_mode,omitempty"` // Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
+}
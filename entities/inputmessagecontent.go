@@ -1,10 +1,5 @@
 package entities
 
-import (
-	"encoding/json"
-	"errors"
-)
-
 // This object represents the content of a message to be sent as a result of an inline query.
 // Telegram clients currently support the following 4 types:
 // - InputTextMessageContent
@@ -27,9 +22,10 @@ func (imc *InputMessageContent) MarshalJSON() ([]byte, error) {
 		*InputVenueMessageContent,
 		InputContactMessageContent,
 		*InputContactMessageContent:
-		return json.Marshal(t)
+		return marshalExactType("InputMessageContent", t)
+	default:
+		return nil, invalidExactType("InputMessageContent", t)
 	}
-	panic(errors.New("invalid InputMessageContent value"))
 }
 
 // Represents the content of a text message to be sent as the result of an inline query.
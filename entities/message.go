@@ -67,3 +67,7 @@ func (t *JsonUnixTime) UnmarshalJSON(b []byte) (err error) {
 	*t = JsonUnixTime(time.Unix(int64(ts), 0))
 	return
 }
+
+func (t JsonUnixTime) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(time.Time(t).Unix(), 10)), nil
+}
@@ -0,0 +1,125 @@
+package entities
+
+// This object represents an error in the Telegram Passport element which was submitted that should be resolved
+// by the user. It should be one of:
+// - PassportElementErrorDataField
+// - PassportElementErrorFrontSide
+// - PassportElementErrorReverseSide
+// - PassportElementErrorSelfie
+// - PassportElementErrorFile
+// - PassportElementErrorFiles
+// - PassportElementErrorTranslationFile
+// - PassportElementErrorTranslationFiles
+// - PassportElementErrorUnspecified
+type PassportElementError struct {
+	ExactType interface{} // put one of the appropriate types above here
+}
+
+// Custom Marshaler allows to substitute PassportElementError with exact type and validate it
+func (pee *PassportElementError) MarshalJSON() ([]byte, error) {
+	switch t := pee.ExactType.(type) {
+	case
+		PassportElementErrorDataField,
+		*PassportElementErrorDataField,
+		PassportElementErrorFrontSide,
+		*PassportElementErrorFrontSide,
+		PassportElementErrorReverseSide,
+		*PassportElementErrorReverseSide,
+		PassportElementErrorSelfie,
+		*PassportElementErrorSelfie,
+		PassportElementErrorFile,
+		*PassportElementErrorFile,
+		PassportElementErrorFiles,
+		*PassportElementErrorFiles,
+		PassportElementErrorTranslationFile,
+		*PassportElementErrorTranslationFile,
+		PassportElementErrorTranslationFiles,
+		*PassportElementErrorTranslationFiles,
+		PassportElementErrorUnspecified,
+		*PassportElementErrorUnspecified:
+		return marshalExactType("PassportElementError", t)
+	default:
+		return nil, invalidExactType("PassportElementError", t)
+	}
+}
+
+// Represents an issue in one of the data fields that was provided by the user. The error is considered resolved
+// when the field's value changes.
+type PassportElementErrorDataField struct {
+	Source    string `json:"source"`     // Error source, must be data
+	Type      string `json:"type"`       // The section of the user's Telegram Passport which has the error, one of “personal_details”, “passport”, “driver_license”, “identity_card”, “internal_passport”, “address”
+	FieldName string `json:"field_name"` // Name of the data field which has the error
+	DataHash  string `json:"data_hash"`  // Base64-encoded data hash
+	Message   string `json:"message"`    // Error message
+}
+
+// Represents an issue with the front side of a document. The error is considered resolved when the file with
+// the front side of the document changes.
+type PassportElementErrorFrontSide struct {
+	Source   string `json:"source"`    // Error source, must be front_side
+	Type     string `json:"type"`      // The section of the user's Telegram Passport which has the issue, one of “passport”, “driver_license”, “identity_card”, “internal_passport”
+	FileHash string `json:"file_hash"` // Base64-encoded hash of the file with the front side of the document
+	Message  string `json:"message"`   // Error message
+}
+
+// Represents an issue with the reverse side of a document. The error is considered resolved when the file with
+// reverse side of the document changes.
+type PassportElementErrorReverseSide struct {
+	Source   string `json:"source"`    // Error source, must be reverse_side
+	Type     string `json:"type"`      // The section of the user's Telegram Passport which has the issue, one of “driver_license”, “identity_card”
+	FileHash string `json:"file_hash"` // Base64-encoded hash of the file with the reverse side of the document
+	Message  string `json:"message"`   // Error message
+}
+
+// Represents an issue with the selfie with a document. The error is considered resolved when the file with
+// the selfie changes.
+type PassportElementErrorSelfie struct {
+	Source   string `json:"source"`    // Error source, must be selfie
+	Type     string `json:"type"`      // The section of the user's Telegram Passport which has the issue, one of “passport”, “driver_license”, “identity_card”, “internal_passport”
+	FileHash string `json:"file_hash"` // Base64-encoded hash of the file with the selfie
+	Message  string `json:"message"`   // Error message
+}
+
+// Represents an issue with a document scan. The error is considered resolved when the file with the document
+// scan changes.
+type PassportElementErrorFile struct {
+	Source   string `json:"source"`    // Error source, must be file
+	Type     string `json:"type"`      // The section of the user's Telegram Passport which has the issue, one of “utility_bill”, “bank_statement”, “rental_agreement”, “passport_registration”, “temporary_registration”
+	FileHash string `json:"file_hash"` // Base64-encoded file hash
+	Message  string `json:"message"`   // Error message
+}
+
+// Represents an issue with a list of scans. The error is considered resolved when the list of files containing
+// the scans changes.
+type PassportElementErrorFiles struct {
+	Source     string   `json:"source"`      // Error source, must be files
+	Type       string   `json:"type"`        // The section of the user's Telegram Passport which has the issue, one of “utility_bill”, “bank_statement”, “rental_agreement”, “passport_registration”, “temporary_registration”
+	FileHashes []string `json:"file_hashes"` // List of base64-encoded file hashes
+	Message    string   `json:"message"`     // Error message
+}
+
+// Represents an issue with one of the files that constitute the translation of a document. The error is considered
+// resolved when the file changes.
+type PassportElementErrorTranslationFile struct {
+	Source   string `json:"source"`    // Error source, must be translation_file
+	Type     string `json:"type"`      // Type of element of the user's Telegram Passport which has the issue, one of “passport”, “driver_license”, “identity_card”, “internal_passport”, “utility_bill”, “bank_statement”, “rental_agreement”, “passport_registration”, “temporary_registration”
+	FileHash string `json:"file_hash"` // Base64-encoded file hash
+	Message  string `json:"message"`   // Error message
+}
+
+// Represents an issue with the translated version of a document. The error is considered resolved when a file
+// with the document translation change.
+type PassportElementErrorTranslationFiles struct {
+	Source     string   `json:"source"`      // Error source, must be translation_files
+	Type       string   `json:"type"`        // Type of element of the user's Telegram Passport which has the issue, one of “passport”, “driver_license”, “identity_card”, “internal_passport”, “utility_bill”, “bank_statement”, “rental_agreement”, “passport_registration”, “temporary_registration”
+	FileHashes []string `json:"file_hashes"` // List of base64-encoded file hashes
+	Message    string   `json:"message"`     // Error message
+}
+
+// Represents an issue in an unspecified place. The error is considered resolved when new data is added.
+type PassportElementErrorUnspecified struct {
+	Source      string `json:"source"`       // Error source, must be unspecified
+	Type        string `json:"type"`         // Type of element of the user's Telegram Passport which has the issue
+	ElementHash string `json:"element_hash"` // Base64-encoded element hash
+	Message     string `json:"message"`      // Error message
+}
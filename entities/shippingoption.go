@@ -0,0 +1,8 @@
+package entities
+
+// This object represents one shipping option.
+type ShippingOption struct {
+	Id     string         `json:"id"`     // Shipping option identifier
+	Title  string         `json:"title"`  // Option title
+	Prices []LabeledPrice `json:"prices"` // List of price portions
+}
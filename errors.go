@@ -0,0 +1,96 @@
+package botan
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+// APIError is returned by Bot methods when Telegram rejects a request. Use errors.As to get it:
+//
+//	var apiErr *botan.APIError
+//	if errors.As(err, &apiErr) && apiErr.IsFloodWait() { ... }
+type APIError struct {
+	Method          string // Telegram Bot API method name, e.g. "sendMessage"
+	ErrorCode       int    // error_code from the API response or HTTP status code if response body can't be decoded
+	Description     string // human-readable description of the error
+	RetryAfter      int    // in case of exceeding flood control, the number of seconds left to wait before the request can be repeated
+	MigrateToChatId int64  // the group has been migrated to a supergroup with this identifier
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram: %s failed with code %d: %s", e.Method, e.ErrorCode, e.Description)
+}
+
+// bot was blocked by the user, kicked from the chat or lacks rights
+func (e *APIError) IsForbidden() bool {
+	return e.ErrorCode == http.StatusForbidden
+}
+
+// chat, message or other target object doesn't exist
+func (e *APIError) IsNotFound() bool {
+	return e.ErrorCode == http.StatusNotFound
+}
+
+// request parameters are invalid
+func (e *APIError) IsBadRequest() bool {
+	return e.ErrorCode == http.StatusBadRequest && e.MigrateToChatId == 0
+}
+
+// flood control exceeded; request can be repeated after RetryAfter seconds
+func (e *APIError) IsFloodWait() bool {
+	return e.ErrorCode == http.StatusTooManyRequests
+}
+
+// group was upgraded to a supergroup; request should be repeated with MigrateToChatId
+func (e *APIError) IsChatMigrated() bool {
+	return e.MigrateToChatId != 0
+}
+
+// builds APIError from unsuccessful API response
+func newAPIError(rawUrl string, apiResponse *entities.ApiResponse) *APIError {
+	return &APIError{
+		Method:          methodFromUrl(rawUrl),
+		ErrorCode:       apiResponse.ErrorCode,
+		Description:     apiResponse.Description,
+		RetryAfter:      apiResponse.RespParams.RetryAfter,
+		MigrateToChatId: apiResponse.RespParams.MigrateToChatId,
+	}
+}
+
+// Log fields describing err: error itself and error_code for API errors
+func errorFields(err error) []interface{} {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return []interface{}{"error_code", apiErr.ErrorCode, "error", err}
+	}
+	return []interface{}{"error", err}
+}
+
+// extracts API method name from its URL; bot token must never get into errors
+func methodFromUrl(rawUrl string) string {
+	return path.Base(strings.SplitN(rawUrl, "?", 2)[0])
+}
+
+// matches bot token in API and file download URLs
+var tokenInUrlRegexp = regexp.MustCompile(`/bot[^/]+/`)
+
+// replaces bot token in URL with a placeholder
+func redactUrl(rawUrl string) string {
+	return tokenInUrlRegexp.ReplaceAllString(rawUrl, "/bot<token>/")
+}
+
+// net/http errors contain full request URL; bot token must never get into errors
+func redactUrlError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactUrl(urlErr.URL)
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package botan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name     string
+		response botantest.Response
+		want     botan.APIError
+		is       string // the only check which must match
+	}{
+		{
+			name:     "forbidden",
+			response: botantest.Error(403, "Forbidden: bot was blocked by the user"),
+			want:     botan.APIError{ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"},
+			is:       "IsForbidden",
+		},
+		{
+			name:     "not found",
+			response: botantest.Error(404, "Not Found"),
+			want:     botan.APIError{ErrorCode: 404, Description: "Not Found"},
+			is:       "IsNotFound",
+		},
+		{
+			name:     "bad request",
+			response: botantest.Error(400, "Bad Request: message text is empty"),
+			want:     botan.APIError{ErrorCode: 400, Description: "Bad Request: message text is empty"},
+			is:       "IsBadRequest",
+		},
+		{
+			name:     "flood wait",
+			response: botantest.FloodWait(17),
+			want:     botan.APIError{ErrorCode: 429, Description: "Too Many Requests: retry after 17", RetryAfter: 17},
+			is:       "IsFloodWait",
+		},
+		{
+			name: "chat migrated",
+			response: botantest.Response{
+				ErrorCode:       400,
+				Description:     "Bad Request: group chat was upgraded to a supergroup chat",
+				MigrateToChatId: -1001234567890,
+			},
+			want: botan.APIError{
+				ErrorCode:       400,
+				Description:     "Bad Request: group chat was upgraded to a supergroup chat",
+				MigrateToChatId: -1001234567890,
+			},
+			is: "IsChatMigrated",
+		},
+	}
+	checks := map[string]func(*botan.APIError) bool{
+		"IsForbidden":    (*botan.APIError).IsForbidden,
+		"IsNotFound":     (*botan.APIError).IsNotFound,
+		"IsBadRequest":   (*botan.APIError).IsBadRequest,
+		"IsFloodWait":    (*botan.APIError).IsFloodWait,
+		"IsChatMigrated": (*botan.APIError).IsChatMigrated,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := botantest.NewServer()
+			defer srv.Close()
+			bot := srv.Bot(t, &botan.BotCallbacksContainer{})
+			srv.Respond(botan.MethodSendMessage, tt.response)
+
+			_, errSend := bot.SendMessage(&botan.SendMessageRequest{ChatId: 42, Text: "hi"})
+			var apiErr *botan.APIError
+			if !errors.As(errSend, &apiErr) {
+				t.Fatalf("got %v, want *APIError", errSend)
+			}
+			want := tt.want
+			want.Method = botan.MethodSendMessage
+			if *apiErr != want {
+				t.Errorf("got %+v, want %+v", *apiErr, want)
+			}
+			for name, check := range checks {
+				if matched := check(apiErr); matched != (name == tt.is) {
+					t.Errorf("%s() = %v", name, matched)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package botan
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+// Logger receives structured log records. Arguments are alternating keys and values, as in log/slog:
+// update_id, method, chat_id, attempt, latency, error_code, error. *slog.Logger implements Logger as is.
+// Bot token is removed from messages and values before they are passed to Logger.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// Returns Logger writing to l; slog.Default() is used if l is nil
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &slogLogger{l: l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (sl *slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	sl.l.Log(context.Background(), slog.LevelDebug, msg, keysAndValues...)
+}
+
+func (sl *slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	sl.l.Log(context.Background(), slog.LevelInfo, msg, keysAndValues...)
+}
+
+func (sl *slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	sl.l.Log(context.Background(), slog.LevelWarn, msg, keysAndValues...)
+}
+
+func (sl *slogLogger) Error(msg string, keysAndValues ...interface{}) {
+	sl.l.Log(context.Background(), slog.LevelError, msg, keysAndValues...)
+}
+
+// default Logger; bot logs nothing unless Config.Logger is set
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...interface{}) {}
+func (noopLogger) Info(string, ...interface{})  {}
+func (noopLogger) Warn(string, ...interface{})  {}
+func (noopLogger) Error(string, ...interface{}) {}
+
+// Wraps Logger set in Config and removes bot token from everything passed to it
+type botLogger struct {
+	out   Logger
+	token string
+}
+
+func newBotLogger(out Logger, token string) *botLogger {
+	if out == nil {
+		out = noopLogger{}
+	}
+	return &botLogger{out: out, token: token}
+}
+
+func (bl *botLogger) debug(msg string, keysAndValues ...interface{}) {
+	bl.out.Debug(bl.redact(msg), bl.redactValues(keysAndValues)...)
+}
+
+func (bl *botLogger) info(msg string, keysAndValues ...interface{}) {
+	bl.out.Info(bl.redact(msg), bl.redactValues(keysAndValues)...)
+}
+
+func (bl *botLogger) warn(msg string, keysAndValues ...interface{}) {
+	bl.out.Warn(bl.redact(msg), bl.redactValues(keysAndValues)...)
+}
+
+func (bl *botLogger) error(msg string, keysAndValues ...interface{}) {
+	bl.out.Error(bl.redact(msg), bl.redactValues(keysAndValues)...)
+}
+
+func (bl *botLogger) redact(s string) string {
+	if bl.token != "" {
+		s = strings.ReplaceAll(s, bl.token, "<token>")
+	}
+	return redactUrl(s)
+}
+
+// strings and errors containing token are replaced with redacted copies; errors without token are passed as is,
+// so that loggers can inspect them
+func (bl *botLogger) redactValues(keysAndValues []interface{}) []interface{} {
+	redacted := make([]interface{}, len(keysAndValues))
+	for i, value := range keysAndValues {
+		switch v := value.(type) {
+		case string:
+			redacted[i] = bl.redact(v)
+		case error:
+			if msg := v.Error(); bl.redact(msg) != msg {
+				redacted[i] = errors.New(bl.redact(msg))
+			} else {
+				redacted[i] = v
+			}
+		default:
+			redacted[i] = value
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,160 @@
+package botan_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+	"github.com/isvinogradov/botan/entities"
+)
+
+type logRecord struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// Logger keeping all records in memory
+type capturingLogger struct {
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (cl *capturingLogger) log(level string, msg string, keysAndValues []interface{}) {
+	record := logRecord{level: level, msg: msg, fields: make(map[string]interface{})}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		record.fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	cl.mu.Lock()
+	cl.records = append(cl.records, record)
+	cl.mu.Unlock()
+}
+
+func (cl *capturingLogger) Debug(msg string, kv ...interface{}) { cl.log("debug", msg, kv) }
+func (cl *capturingLogger) Info(msg string, kv ...interface{})  { cl.log("info", msg, kv) }
+func (cl *capturingLogger) Warn(msg string, kv ...interface{})  { cl.log("warn", msg, kv) }
+func (cl *capturingLogger) Error(msg string, kv ...interface{}) { cl.log("error", msg, kv) }
+
+// Returns the first record with the message and all of the fields; fails the test if there's none
+func (cl *capturingLogger) find(t *testing.T, msg string, fields ...string) logRecord {
+	t.Helper()
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	for _, record := range cl.records {
+		if record.msg != msg {
+			continue
+		}
+		hasAll := true
+		for _, field := range fields {
+			if _, ok := record.fields[field]; !ok {
+				hasAll = false
+			}
+		}
+		if hasAll {
+			return record
+		}
+	}
+	t.Fatalf("no %q record with fields %v in %+v", msg, fields, cl.records)
+	return logRecord{}
+}
+
+// Fails the test if the token or its secret part appears in any message or field
+func (cl *capturingLogger) assertNoToken(t *testing.T, token string) {
+	t.Helper()
+	secret := token[strings.IndexByte(token, ':')+1:]
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	for _, record := range cl.records {
+		if strings.Contains(record.msg, secret) {
+			t.Errorf("token in message %q", record.msg)
+		}
+		for key, value := range record.fields {
+			if formatted := fmt.Sprintf("%+v", value); strings.Contains(formatted, secret) {
+				t.Errorf("token in field %s of %q: %s", key, record.msg, formatted)
+			}
+		}
+	}
+}
+
+func newLoggedBot(t *testing.T, conf *botan.Config, callbacks *botan.BotCallbacksContainer) (*botan.Bot, *capturingLogger) {
+	t.Helper()
+	logger := &capturingLogger{}
+	conf.Logger = logger
+	bot, errBot := botan.NewBot(conf, callbacks)
+	if errBot != nil {
+		t.Fatal(errBot)
+	}
+	return bot, logger
+}
+
+// Runs getUpdates loop until done is closed or timeout
+func runFor(bot *botan.Bot, done <-chan struct{}, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	bot.Run(ctx)
+}
+
+func TestLoggerRedactsTokenOnNetworkErrors(t *testing.T) {
+	dead := httptest.NewServer(nil)
+	dead.Close() // connections are refused
+	conf := &botan.Config{Token: botantest.DefaultToken, APIBaseURL: dead.URL, GetUpdatesFailCooldownSeconds: 1}
+	bot, logger := newLoggedBot(t, conf, &botan.BotCallbacksContainer{})
+
+	if _, errSend := bot.SendMessage(&botan.SendMessageRequest{ChatId: 42, Text: "hi"}); errSend == nil {
+		t.Fatal("request to a closed server succeeded")
+	}
+	failed := logger.find(t, "request failed", "method", "chat_id", "latency", "error")
+	if failed.fields["method"] != botan.MethodSendMessage || failed.fields["chat_id"] != "42" {
+		t.Errorf("got %+v", failed.fields)
+	}
+
+	runFor(bot, nil, 500*time.Millisecond)
+	logger.find(t, "getUpdates failed", "error")
+	logger.assertNoToken(t, botantest.DefaultToken)
+}
+
+func TestLoggerRedactsTokenOnApiErrors(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+	srv.Respond(botan.MethodSendMessage, botantest.Error(403, "Forbidden: bot was blocked by the user"))
+	srv.Respond(botan.MethodGetUpdates, botantest.Error(502, "Bad Gateway"))
+	srv.QueueMessage(42, "hi")
+
+	handled := make(chan struct{})
+	bot, logger := newLoggedBot(t, srv.Config(), &botan.BotCallbacksContainer{
+		OnMessage: func(bot *botan.Bot, msg *entities.Message) error {
+			close(handled)
+			return nil
+		},
+	})
+
+	if _, errSend := bot.SendMessage(&botan.SendMessageRequest{ChatId: 42, Text: "hi"}); errSend == nil {
+		t.Fatal("rejected request succeeded")
+	}
+	failed := logger.find(t, "request failed", "method", "chat_id", "latency", "error_code", "error")
+	if failed.fields["error_code"] != 403 {
+		t.Errorf("got %+v", failed.fields)
+	}
+
+	runFor(bot, handled, 5*time.Second)
+	if polled := logger.find(t, "getUpdates failed", "error_code"); polled.fields["error_code"] != 502 {
+		t.Errorf("got %+v", polled.fields)
+	}
+	if processed := logger.find(t, "processing update", "update_id"); processed.fields["update_id"] != 1 {
+		t.Errorf("got %+v", processed.fields)
+	}
+	logger.assertNoToken(t, srv.Token)
+}
@@ -1,21 +1,46 @@
 package botan
 
 import (
+	"bytes"
+	"encoding/json"
+
 	en "github.com/isvinogradov/botan/entities"
 )
 
-// A simple method for testing your bot's auth token. Requires no parameters. Returns basic information
-// about the bot in form of a User object.
-func (bot *Bot) GetMe() (*en.User, error) {
-	var target en.User
+// Result of methods editing messages: the edited Message if the message was sent by the bot, otherwise (for inline
+// messages) True is returned and message stays nil.
+type editResult struct {
+	message *en.Message
+}
+
+func (er *editResult) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("true")) {
+		return nil
+	}
+	er.message = new(en.Message)
+	return json.Unmarshal(b, er.message)
+}
+
+// Calls API method and decodes its result to T. Methods returning True on success use bool: the result is true
+// whenever the call succeeds.
+func call[T any](bot *Bot, method string, req interface{}) (T, error) {
+	var result T
 	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.getMe,
-		nil,
-		&target,
+		bot.context(),
+		bot.urls.method(method),
+		req,
+		&result,
 	); postErr != nil {
-		return nil, postErr
+		var zero T
+		return zero, postErr
 	}
-	return &target, nil
+	return result, nil
+}
+
+// A simple method for testing your bot's auth token. Requires no parameters. Returns basic information
+// about the bot in form of a User object.
+func (bot *Bot) GetMe() (*en.User, error) {
+	return call[*en.User](bot, MethodGetMe, nil)
 }
 
 // Use this method to send text messages. On success, the sent Message is returned.
@@ -30,24 +55,14 @@ type SendMessageRequest struct {
 	DisableWebPagePreview bool            `json:"disable_web_page_preview,omitempty"` // Optional. Disables link previews for links in this message
 }
 
-// todo connect target and url (mapping)
 func (bot *Bot) SendMessage(msg *SendMessageRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendMessage,
-		msg,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendMessage, msg)
 }
 
 // Use this method to send photos. On success, the sent Message is returned.
-// todo: Photo can be InputFile
 type SendPhotoRequest struct {
 	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-	Photo               string          `json:"photo"`                          // Photo to send. Pass a file_id as String to send a photo that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a photo from the Internet, or upload a new photo using multipart/form-data
+	Photo               *en.InputFile   `json:"photo"`                          // Photo to send. Pass a file_id as String to send a photo that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a photo from the Internet, or upload a new photo using multipart/form-data
 	Caption             string          `json:"caption,omitempty"`              // Optional. Photo caption (may also be used when resending photos by file_id), 0-1024 characters
 	ParseMode           string          `json:"parse_mode,omitempty"`           // Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
 	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional. Sends the message silently. Users will receive a notification with no sound.
@@ -56,15 +71,7 @@ type SendPhotoRequest struct {
 }
 
 func (bot *Bot) SendPhoto(sPhoto *SendPhotoRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendPhoto,
-		sPhoto,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendPhoto, sPhoto)
 }
 
 // Use this method to send answers to callback queries sent from inline keyboards. The answer will be displayed
@@ -78,14 +85,7 @@ type AnswerCallbackQueryRequest struct {
 }
 
 func (bot *Bot) AnswerCallbackQuery(answerCbQ *AnswerCallbackQueryRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.answerCallback,
-		answerCbQ,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodAnswerCallbackQuery, answerCbQ)
 }
 
 // Use this method to edit only the reply markup of messages sent by the bot or via the bot (for inline bots).
@@ -97,16 +97,10 @@ type EditMessageReplyMarkupRequest struct {
 	ReplyMarkup     *en.InlineKeyboardMarkup `json:"reply_markup,omitempty"`      // Optional  A JSON-serialized object for an inline keyboard.
 }
 
+// Returns nil Message if inline message was edited.
 func (bot *Bot) EditMessageReplyMarkup(editReplyMkup *EditMessageReplyMarkupRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.updateMessageMarkup,
-		editReplyMkup,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	result, errCall := call[editResult](bot, MethodEditReplyMarkup, editReplyMkup)
+	return result.message, errCall
 }
 
 // Use this method to send answers to an inline query. On success, True is returned. No more than 50 results
@@ -123,14 +117,7 @@ type AnswerInlineQueryRequest struct {
 }
 
 func (bot *Bot) AnswerInlineQuery(answer *AnswerInlineQueryRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.answerInlineQuery,
-		answer,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodAnswerInlineQuery, answer)
 }
 
 // Use this method when you need to tell the user that something is happening on the bot's side. We only recommend
@@ -147,14 +134,7 @@ type SendChatActionRequest struct {
 }
 
 func (bot *Bot) SendChatAction(chatAction *SendChatActionRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendChatAction,
-		chatAction,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodSendChatAction, chatAction)
 }
 
 // Use this method to send a native poll. A native poll can't be sent to a private chat.
@@ -169,15 +149,7 @@ type SendPollRequest struct {
 }
 
 func (bot *Bot) SendPoll(poll *SendPollRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendPoll,
-		poll,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendPoll, poll)
 }
 
 // Use this method to stop a poll which was sent by the bot.
@@ -189,37 +161,20 @@ type StopPollRequest struct {
 }
 
 func (bot *Bot) StopPoll(poll *StopPollRequest) (*en.Poll, error) {
-	var target en.Poll
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.stopPoll,
-		poll,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Poll](bot, MethodStopPoll, poll)
 }
 
 // Use this method to send .webp stickers. On success, the sent Message is returned.
-// todo sticker=InputFile
 type SendStickerRequest struct {
 	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-	Sticker             string          `json:"sticker"`                        // Sticker to send. Pass a file_id as String to send a file that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a .webp file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
+	Sticker             *en.InputFile   `json:"sticker"`                        // Sticker to send. Pass a file_id as String to send a file that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a .webp file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
 	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
 	ReplyToMessageId    int             `json:"reply_to_message_id,omitempty"`  // Optional 	If the message is a reply, ID of the original message
 	ReplyMarkup         *en.ReplyMarkup `json:"reply_markup,omitempty"`         // Optional 	Additional interface options. A JSON-serialized object for an inline keyboard, custom reply keyboard, instructions to remove reply keyboard or to force a reply from the user.
 }
 
 func (bot *Bot) SendSticker(stickerReq *SendStickerRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendSticker,
-		stickerReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendSticker, stickerReq)
 }
 
 // Use this method to get up to date information about the chat (current name of the user for one-on-one
@@ -229,15 +184,7 @@ type GetChatRequest struct {
 }
 
 func (bot *Bot) GetChat(getChatReq *GetChatRequest) (*en.Chat, error) {
-	var target en.Chat
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.getChat,
-		getChatReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Chat](bot, MethodGetChat, getChatReq)
 }
 
 // Use this method to get a list of profile pictures for a user. Returns a UserProfilePhotos object.
@@ -248,15 +195,7 @@ type GetUserProfilePhotosRequest struct {
 }
 
 func (bot *Bot) GetUserProfilePhotos(getPhotReq *GetUserProfilePhotosRequest) (*en.UserProfilePhotos, error) {
-	var target en.UserProfilePhotos
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.getUserProfilePhotos,
-		getPhotReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.UserProfilePhotos](bot, MethodGetUserProfilePhotos, getPhotReq)
 }
 
 // Use this method to forward messages of any kind. On success, the sent Message is returned.
@@ -268,15 +207,7 @@ type ForwardMessageRequest struct {
 }
 
 func (bot *Bot) ForwardMessage(fwdMsgReq *ForwardMessageRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.forwardMessage,
-		fwdMsgReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodForwardMessage, fwdMsgReq)
 }
 
 // Use this method to change the title of a chat. Titles can't be changed for private chats. The bot must be
@@ -289,26 +220,18 @@ type SetChatTitleRequest struct {
 }
 
 func (bot *Bot) SetChatTitle(setChatTReq *SetChatTitleRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.setChatTitle,
-		setChatTReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodSetChatTitle, setChatTReq)
 }
 
 // Use this method to send animation files (GIF or H.264/MPEG-4 AVC video without sound). On success, the sent Message
 // is returned. Bots can currently send animation files of up to 50 MB in size, this limit may be changed in the future.
-// todo: thumb/animation can be InputFile
 type SendAnimationRequest struct {
 	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-	Animation           string          `json:"animation"`                      // Animation to send. Pass a file_id as String to send an animation that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get an animation from the Internet, or upload a new animation using multipart/form-data. More info on Sending Files »
+	Animation           *en.InputFile   `json:"animation"`                      // Animation to send. Pass a file_id as String to send an animation that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get an animation from the Internet, or upload a new animation using multipart/form-data. More info on Sending Files »
 	Duration            int             `json:"duration,omitempty"`             // Optional 	Duration of sent animation in seconds
 	Width               int             `json:"width,omitempty"`                // Optional 	Animation width
 	Height              int             `json:"height,omitempty"`               // Optional 	Animation height
-	Thumb               string          `json:"thumb,omitempty"`                // Optional 	Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was uploaded using multipart/form-data under <file_attach_name>. More info on Sending Files »
+	Thumb               *en.InputFile   `json:"thumb,omitempty"`                // Optional 	Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused and can be only uploaded as a new file.
 	Caption             string          `json:"caption,omitempty"`              // Optional 	Animation caption (may also be used when resending animation by file_id), 0-1024 characters
 	ParseMode           string          `json:"parse_mode,omitempty"`           // Optional 	Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
 	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
@@ -317,15 +240,7 @@ type SendAnimationRequest struct {
 }
 
 func (bot *Bot) SendAnimation(sendAnReq *SendAnimationRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendAnimation,
-		sendAnReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendAnimation, sendAnReq)
 }
 
 // Use this method to send audio files, if you want Telegram clients to display the file as a playable voice message.
@@ -334,7 +249,7 @@ func (bot *Bot) SendAnimation(sendAnReq *SendAnimationRequest) (*en.Message, err
 // to 50 MB in size, this limit may be changed in the future.
 type SendVoiceRequest struct {
 	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-	Voice               string          `json:"voice"`                          // Audio file to send. Pass a file_id as String to send a file that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
+	Voice               *en.InputFile   `json:"voice"`                          // Audio file to send. Pass a file_id as String to send a file that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
 	Caption             string          `json:"caption,omitempty"`              // Optional 	Voice message caption, 0-1024 characters
 	ParseMode           string          `json:"parse_mode,omitempty"`           // Optional 	Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
 	Duration            int             `json:"duration,omitempty"`             // Optional 	Duration of the voice message in seconds
@@ -344,35 +259,43 @@ type SendVoiceRequest struct {
 }
 
 func (bot *Bot) SendVoice(sendVoiceReq *SendVoiceRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendVoice,
-		sendVoiceReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendVoice, sendVoiceReq)
+}
+
+// Use this method to send audio files, if you want Telegram clients to display them in the music player. Your audio
+// must be in the .MP3 or .M4A format. On success, the sent Message is returned. Bots can currently send audio files
+// of up to 50 MB in size, this limit may be changed in the future.
+// For sending voice messages, use the sendVoice method instead.
+type SendAudioRequest struct {
+	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	Audio               *en.InputFile   `json:"audio"`                          // Audio file to send. Pass a file_id as String to send an audio file that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get an audio file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
+	Caption             string          `json:"caption,omitempty"`              // Optional 	Audio caption, 0-1024 characters
+	ParseMode           string          `json:"parse_mode,omitempty"`           // Optional 	Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
+	Duration            int             `json:"duration,omitempty"`             // Optional 	Duration of the audio in seconds
+	Performer           string          `json:"performer,omitempty"`            // Optional 	Performer
+	Title               string          `json:"title,omitempty"`                // Optional 	Track name
+	Thumb               *en.InputFile   `json:"thumb,omitempty"`                // Optional 	Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused and can be only uploaded as a new file.
+	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
+	ReplyToMessageId    int             `json:"reply_to_message_id,omitempty"`  // Optional 	If the message is a reply, ID of the original message
+	ReplyMarkup         *en.ReplyMarkup `json:"reply_markup,omitempty"`         // Optional 	Additional interface options. A JSON-serialized object for an inline keyboard, custom reply keyboard, instructions to remove reply keyboard or to force a reply from the user.
+}
+
+func (bot *Bot) SendAudio(sendAudioReq *SendAudioRequest) (*en.Message, error) {
+	return call[*en.Message](bot, MethodSendAudio, sendAudioReq)
 }
 
 // Use this method to get basic info about a file and prepare it for downloading. For the moment, bots can download files
 // of up to 20MB in size. On success, a File object is returned. The file can then be downloaded via the
 // link https://api.telegram.org/file/bot<token>/<file_path>, where <file_path> is taken from the response. It is guaranteed
 // that the link will be valid for at least 1 hour. When the link expires, a new one can be requested by calling getFile again.
+// Local Bot API server started with --local returns absolute path of the file on its host as file_path; set
+// Config.LocalMode to make DownloadFile read such files from the local file system.
 type GetFileRequest struct {
 	FileId string `json:"file_id"` // File identifier to get info about
 }
 
 func (bot *Bot) GetFile(getFileReq *GetFileRequest) (*en.File, error) {
-	var target en.File
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.getFile,
-		getFileReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.File](bot, MethodGetFile, getFileReq)
 }
 
 type SendLocationRequest struct {
@@ -387,22 +310,13 @@ type SendLocationRequest struct {
 
 // Use this method to send point on the map. On success, the sent Message is returned.
 func (bot *Bot) SendLocation(sendLocReq *SendLocationRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendLocation,
-		sendLocReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendLocation, sendLocReq)
 }
 
-// todo document and thumb is an inputfile or string
 type SendDocumentRequest struct {
 	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-	Document            string          `json:"document"`                       // File to send. Pass a file_id as String to send a file that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
-	Thumb               string          `json:"thumb,omitempty"`                // Optional 	Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was uploaded using multipart/form-data under <file_attach_name>. More info on Sending Files »
+	Document            *en.InputFile   `json:"document"`                       // File to send. Pass a file_id as String to send a file that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
+	Thumb               *en.InputFile   `json:"thumb,omitempty"`                // Optional 	Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused and can be only uploaded as a new file.
 	Caption             string          `json:"caption,omitempty"`              // Optional 	Document caption (may also be used when resending documents by file_id), 0-1024 characters
 	ParseMode           string          `json:"parse_mode,omitempty"`           // Optional 	Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
 	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
@@ -413,158 +327,166 @@ type SendDocumentRequest struct {
 // Use this method to send general files. On success, the sent Message is returned. Bots can currently send files
 // of any type of up to 50 MB in size, this limit may be changed in the future.
 func (bot *Bot) SendDocument(sendDocReq *SendDocumentRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendDocument,
-		sendDocReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendDocument, sendDocReq)
 }
 
-////////////////////////////////////////////////////////
-//////////////// TODO ///////////////////////////////////
-////////////////////////////////////////////////////////
-type SendVideoRequest struct{}
+type SendVideoRequest struct {
+	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	Video               *en.InputFile   `json:"video"`                          // Video to send. Pass a file_id as String to send a video that exists on the Telegram servers (recommended), pass an HTTP URL as a String for Telegram to get a video from the Internet, or upload a new video using multipart/form-data. More info on Sending Files »
+	Duration            int             `json:"duration,omitempty"`             // Optional 	Duration of sent video in seconds
+	Width               int             `json:"width,omitempty"`                // Optional 	Video width
+	Height              int             `json:"height,omitempty"`               // Optional 	Video height
+	Thumb               *en.InputFile   `json:"thumb,omitempty"`                // Optional 	Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused and can be only uploaded as a new file.
+	Caption             string          `json:"caption,omitempty"`              // Optional 	Video caption (may also be used when resending videos by file_id), 0-1024 characters
+	ParseMode           string          `json:"parse_mode,omitempty"`           // Optional 	Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
+	SupportsStreaming   bool            `json:"supports_streaming,omitempty"`   // Optional 	Pass True, if the uploaded video is suitable for streaming
+	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
+	ReplyToMessageId    int             `json:"reply_to_message_id,omitempty"`  // Optional 	If the message is a reply, ID of the original message
+	ReplyMarkup         *en.ReplyMarkup `json:"reply_markup,omitempty"`         // Optional 	Additional interface options. A JSON-serialized object for an inline keyboard, custom reply keyboard, instructions to remove reply keyboard or to force a reply from the user.
+}
 
 // Use this method to send video files, Telegram clients support mp4 videos (other formats may be sent as Document).
 // On success, the sent Message is returned. Bots can currently send video files of up to 50 MB in size, this limit
 // may be changed in the future.
 func (bot *Bot) SendVideo(svReq *SendVideoRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendVideo,
-		svReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendVideo, svReq)
 }
 
-type SendVideoNoteRequest struct{}
+type SendVideoNoteRequest struct {
+	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	VideoNote           *en.InputFile   `json:"video_note"`                     // Video note to send. Pass a file_id as String to send a video note that exists on the Telegram servers (recommended) or upload a new video using multipart/form-data. More info on Sending Files ». Sending video notes by a URL is currently unsupported
+	Duration            int             `json:"duration,omitempty"`             // Optional 	Duration of sent video in seconds
+	Length              int             `json:"length,omitempty"`               // Optional 	Video width and height, i.e. diameter of the video message
+	Thumb               *en.InputFile   `json:"thumb,omitempty"`                // Optional 	Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused and can be only uploaded as a new file.
+	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
+	ReplyToMessageId    int             `json:"reply_to_message_id,omitempty"`  // Optional 	If the message is a reply, ID of the original message
+	ReplyMarkup         *en.ReplyMarkup `json:"reply_markup,omitempty"`         // Optional 	Additional interface options. A JSON-serialized object for an inline keyboard, custom reply keyboard, instructions to remove reply keyboard or to force a reply from the user.
+}
 
 // As of v.4.0, Telegram clients support rounded square mp4 videos of up to 1 minute long. Use this method to send
 // video messages. On success, the sent Message is returned.
 func (bot *Bot) SendVideoNote(svnReq *SendVideoNoteRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendVideoNote,
-		svnReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendVideoNote, svnReq)
 }
 
-type SendMediaGroupRequest struct{}
+type SendMediaGroupRequest struct {
+	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	Media               []en.InputMedia `json:"media"`                          // A JSON-serialized array describing photos and videos to be sent, must include 2–10 items
+	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the messages silently. Users will receive a notification with no sound.
+	ReplyToMessageId    int             `json:"reply_to_message_id,omitempty"`  // Optional 	If the messages are a reply, ID of the original message
+}
 
 // Use this method to send a group of photos or videos as an album. On success, an array of the sent Messages is returned.
 func (bot *Bot) SendMediaGroup(smgReq *SendMediaGroupRequest) ([]*en.Message, error) {
-	var target []*en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendMediaGroup,
-		smgReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return target, nil
+	return call[[]*en.Message](bot, MethodSendMediaGroup, smgReq)
+}
+
+type EditMessageLiveLocationRequest struct {
+	ChatId          interface{}              `json:"chat_id,omitempty"`           // Optional 	Required if inline_message_id is not specified. Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	MessageId       int                      `json:"message_id,omitempty"`        // Optional 	Required if inline_message_id is not specified. Identifier of the message to edit
+	InlineMessageId string                   `json:"inline_message_id,omitempty"` // Optional 	Required if chat_id and message_id are not specified. Identifier of the inline message
+	Latitude        float32                  `json:"latitude"`                    // Latitude of new location
+	Longitude       float32                  `json:"longitude"`                   // Longitude of new location
+	ReplyMarkup     *en.InlineKeyboardMarkup `json:"reply_markup,omitempty"`      // Optional 	A JSON-serialized object for a new inline keyboard.
+}
+
+// Use this method to edit live location messages. A location can be edited until its live_period expires or editing
+// is explicitly disabled by a call to stopMessageLiveLocation. On success, if the edited message was sent by the bot,
+// the edited Message is returned, otherwise True is returned (nil Message is returned in that case).
+func (bot *Bot) EditMessageLiveLocation(emllReq *EditMessageLiveLocationRequest) (*en.Message, error) {
+	result, errCall := call[editResult](bot, MethodEditMessageLiveLocation, emllReq)
+	return result.message, errCall
+}
+
+type StopMessageLiveLocationRequest struct {
+	ChatId          interface{}              `json:"chat_id,omitempty"`           // Optional 	Required if inline_message_id is not specified. Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	MessageId       int                      `json:"message_id,omitempty"`        // Optional 	Required if inline_message_id is not specified. Identifier of the message with live location to stop
+	InlineMessageId string                   `json:"inline_message_id,omitempty"` // Optional 	Required if chat_id and message_id are not specified. Identifier of the inline message
+	ReplyMarkup     *en.InlineKeyboardMarkup `json:"reply_markup,omitempty"`      // Optional 	A JSON-serialized object for a new inline keyboard.
 }
 
-//type EditMessageLiveLocationRequest struct{}
-//
-//// Use this method to edit live location messages. A location can be edited until its live_period expires or editing
-//// is explicitly disabled by a call to stopMessageLiveLocation. On success, if the edited message was sent by the bot,
-//// the edited Message is returned, otherwise True is returned.
-//func (bot *Bot) EditMessageLiveLocation(emllReq *EditMessageLiveLocationRequest) (zzz, error) {}
-//
-//type StopMessageLiveLocationRequest struct{}
-//
-//// Use this method to stop updating a live location message before live_period expires. On success, if the message was
-//// sent by the bot, the sent Message is returned, otherwise True is returned.
-//func (bot *Bot) StopMessageLiveLocation(smllReq *StopMessageLiveLocationRequest) (zzz, error) {}
+// Use this method to stop updating a live location message before live_period expires. On success, if the message was
+// sent by the bot, the sent Message is returned, otherwise True is returned (nil Message is returned in that case).
+func (bot *Bot) StopMessageLiveLocation(smllReq *StopMessageLiveLocationRequest) (*en.Message, error) {
+	result, errCall := call[editResult](bot, MethodStopMessageLiveLocation, smllReq)
+	return result.message, errCall
+}
 
-type SendVenueRequest struct{}
+type SendVenueRequest struct {
+	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	Latitude            float32         `json:"latitude"`                       // Latitude of the venue
+	Longitude           float32         `json:"longitude"`                      // Longitude of the venue
+	Title               string          `json:"title"`                          // Name of the venue
+	Address             string          `json:"address"`                        // Address of the venue
+	FoursquareId        string          `json:"foursquare_id,omitempty"`        // Optional 	Foursquare identifier of the venue
+	FoursquareType      string          `json:"foursquare_type,omitempty"`      // Optional 	Foursquare type of the venue, if known. (For example, “arts_entertainment/default”, “arts_entertainment/aquarium” or “food/icecream”.)
+	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
+	ReplyToMessageId    int             `json:"reply_to_message_id,omitempty"`  // Optional 	If the message is a reply, ID of the original message
+	ReplyMarkup         *en.ReplyMarkup `json:"reply_markup,omitempty"`         // Optional 	Additional interface options. A JSON-serialized object for an inline keyboard, custom reply keyboard, instructions to remove reply keyboard or to force a reply from the user.
+}
 
 // Use this method to send information about a venue. On success, the sent Message is returned.
 func (bot *Bot) SendVenue(svenReq *SendVenueRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendVenue,
-		svenReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendVenue, svenReq)
 }
 
-type SendContactRequest struct{}
+type SendContactRequest struct {
+	ChatId              interface{}     `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	PhoneNumber         string          `json:"phone_number"`                   // Contact's phone number
+	FirstName           string          `json:"first_name"`                     // Contact's first name
+	LastName            string          `json:"last_name,omitempty"`            // Optional 	Contact's last name
+	Vcard               string          `json:"vcard,omitempty"`                // Optional 	Additional data about the contact in the form of a vCard, 0-2048 bytes
+	DisableNotification bool            `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
+	ReplyToMessageId    int             `json:"reply_to_message_id,omitempty"`  // Optional 	If the message is a reply, ID of the original message
+	ReplyMarkup         *en.ReplyMarkup `json:"reply_markup,omitempty"`         // Optional 	Additional interface options. A JSON-serialized object for an inline keyboard, custom reply keyboard, instructions to remove keyboard or to force a reply from the user.
+}
 
 // Use this method to send phone contacts. On success, the sent Message is returned.
 func (bot *Bot) SendContact(sconReq *SendContactRequest) (*en.Message, error) {
-	var target en.Message
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.sendContact,
-		sconReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.Message](bot, MethodSendContact, sconReq)
 }
 
-type KickChatMemberRequest struct{}
+type KickChatMemberRequest struct {
+	ChatId    interface{} `json:"chat_id"`              // Unique identifier for the target group or username of the target supergroup or channel (in the format @channelusername)
+	UserId    int         `json:"user_id"`              // Unique identifier of the target user
+	UntilDate int         `json:"until_date,omitempty"` // Optional 	Date when the user will be unbanned, unix time. If user is banned for more than 366 days or less than 30 seconds from the current time they are considered to be banned forever
+}
 
 // Use this method to kick a user from a group, a supergroup or a channel. In the case of supergroups and channels,
 // the user will not be able to return to the group on their own using invite links, etc., unless unbanned first.
 // The bot must be an administrator in the chat for this to work and must have the appropriate admin rights.
 // Returns True on success.
 func (bot *Bot) KickChatMember(kcmReq *KickChatMemberRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.kickChatMember,
-		kcmReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodKickChatMember, kcmReq)
 }
 
-type UnbanChatMemberRequest struct{}
+type UnbanChatMemberRequest struct {
+	ChatId interface{} `json:"chat_id"` // Unique identifier for the target group or username of the target supergroup or channel (in the format @username)
+	UserId int         `json:"user_id"` // Unique identifier of the target user
+}
 
 // Use this method to unban a previously kicked user in a supergroup or channel. The user will not return to the group
 // or channel automatically, but will be able to join via link, etc. The bot must be an administrator for this to work.
 // Returns True on success.
 func (bot *Bot) UnbanChatMember(ucmReq *UnbanChatMemberRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.unbanChatMember,
-		ucmReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodUnbanChatMember, ucmReq)
 }
 
-type RestrictChatMemberRequest struct{}
+type RestrictChatMemberRequest struct {
+	ChatId                interface{} `json:"chat_id"`                             // Unique identifier for the target chat or username of the target supergroup (in the format @supergroupusername)
+	UserId                int         `json:"user_id"`                             // Unique identifier of the target user
+	UntilDate             int         `json:"until_date,omitempty"`                // Optional 	Date when restrictions will be lifted for the user, unix time. If user is restricted for more than 366 days or less than 30 seconds from the current time, they are considered to be restricted forever
+	CanSendMessages       bool        `json:"can_send_messages,omitempty"`         // Optional 	Pass True, if the user can send text messages, contacts, locations and venues
+	CanSendMediaMessages  bool        `json:"can_send_media_messages,omitempty"`   // Optional 	Pass True, if the user can send audios, documents, photos, videos, video notes and voice notes, implies can_send_messages
+	CanSendOtherMessages  bool        `json:"can_send_other_messages,omitempty"`   // Optional 	Pass True, if the user can send animations, games, stickers and use inline bots, implies can_send_media_messages
+	CanAddWebPagePreviews bool        `json:"can_add_web_page_previews,omitempty"` // Optional 	Pass True, if the user may add web page previews to their messages, implies can_send_media_messages
+}
 
 // Use this method to restrict a user in a supergroup. The bot must be an administrator in the supergroup for this
 // to work and must have the appropriate admin rights. Pass True for all boolean parameters to lift restrictions from
 // a user. Returns True on success.
 func (bot *Bot) RestrictChatMember(rcmReq *RestrictChatMemberRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.restrictChatMember,
-		rcmReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodRestrictChatMember, rcmReq)
 }
 
 type PromoteChatMemberRequest struct {
@@ -584,46 +506,29 @@ type PromoteChatMemberRequest struct {
 // the chat for this to work and must have the appropriate admin rights. Pass False for all boolean parameters to
 // demote a user. Returns True on success.
 func (bot *Bot) PromoteChatMember(pcmReq *PromoteChatMemberRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.promoteChatMember,
-		pcmReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodPromoteChatMember, pcmReq)
 }
 
-type ExportChatInviteLinkRequest struct{}
+type ExportChatInviteLinkRequest struct {
+	ChatId interface{} `json:"chat_id"` // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+}
 
 // Use this method to generate a new invite link for a chat; any previously generated link is revoked. The bot must
 // be an administrator in the chat for this to work and must have the appropriate admin rights. Returns the new invite
 // link as String on success.
 func (bot *Bot) ExportChatInviteLink(ecilReq *ExportChatInviteLinkRequest) (string, error) {
-	var target string
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.exportChatInviteLink,
-		ecilReq,
-		&target,
-	); postErr != nil {
-		return "", postErr
-	}
-	return target, nil
+	return call[string](bot, MethodExportChatInviteLink, ecilReq)
 }
 
-type SetChatPhotoRequest struct{}
+type SetChatPhotoRequest struct {
+	ChatId interface{}   `json:"chat_id"` // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	Photo  *en.InputFile `json:"photo"`   // New chat photo, uploaded using multipart/form-data
+}
 
 // Use this method to set a new profile photo for the chat. Photos can't be changed for private chats. The bot must be
 // an administrator in the chat for this to work and must have the appropriate admin rights. Returns True on success.
 func (bot *Bot) SetChatPhoto(scpReq *SetChatPhotoRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.setChatPhoto,
-		scpReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodSetChatPhoto, scpReq)
 }
 
 type DeleteChatPhotoRequest struct {
@@ -633,29 +538,18 @@ type DeleteChatPhotoRequest struct {
 // Use this method to delete a chat photo. Photos can't be changed for private chats. The bot must be an administrator
 // in the chat for this to work and must have the appropriate admin rights. Returns True on success.
 func (bot *Bot) DeleteChatPhoto(dcpReq *DeleteChatPhotoRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.deleteChatPhoto,
-		dcpReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodDeleteChatPhoto, dcpReq)
 }
 
-type SetChatDescriptionRequest struct{}
+type SetChatDescriptionRequest struct {
+	ChatId      interface{} `json:"chat_id"`               // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	Description string      `json:"description,omitempty"` // Optional 	New chat description, 0-255 characters
+}
 
 // Use this method to change the description of a supergroup or a channel. The bot must be an administrator in the chat
 // for this to work and must have the appropriate admin rights. Returns True on success.
 func (bot *Bot) SetChatDescription(scdReq *SetChatDescriptionRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.setChatDescription,
-		scdReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodSetChatDescription, scdReq)
 }
 
 type PinChatMessageRequest struct {
@@ -668,14 +562,7 @@ type PinChatMessageRequest struct {
 // for this to work and must have the ‘can_pin_messages’ admin right in the supergroup or ‘can_edit_messages’ admin
 // right in the channel. Returns True on success.
 func (bot *Bot) PinChatMessage(picmReq *PinChatMessageRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.pinChatMessage,
-		picmReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodPinChatMessage, picmReq)
 }
 
 type UnpinChatMessageRequest struct {
@@ -686,14 +573,7 @@ type UnpinChatMessageRequest struct {
 // chat for this to work and must have the ‘can_pin_messages’ admin right in the supergroup or ‘can_edit_messages’
 // admin right in the channel. Returns True on success.
 func (bot *Bot) UnpinChatMessage(upcmReq *UnpinChatMessageRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.unpinChatMessage,
-		upcmReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodUnpinChatMessage, upcmReq)
 }
 
 type LeaveChatRequest struct {
@@ -702,14 +582,7 @@ type LeaveChatRequest struct {
 
 // Use this method for your bot to leave a group, supergroup or channel. Returns True on success.
 func (bot *Bot) LeaveChat(lcmReq *LeaveChatRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.leaveChat,
-		lcmReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodLeaveChat, lcmReq)
 }
 
 type GetChatAdministratorsRequest struct {
@@ -720,15 +593,7 @@ type GetChatAdministratorsRequest struct {
 // contains information about all chat administrators except other bots. If the chat is a group or a supergroup and
 // no administrators were appointed, only the creator will be returned.
 func (bot *Bot) GetChatAdministrators(gcaReq *GetChatAdministratorsRequest) ([]*en.ChatMember, error) {
-	var target []*en.ChatMember
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.getChatAdministrators,
-		gcaReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return target, nil
+	return call[[]*en.ChatMember](bot, MethodGetChatAdministrators, gcaReq)
 }
 
 type GetChatMembersCountRequest struct {
@@ -737,30 +602,17 @@ type GetChatMembersCountRequest struct {
 
 // Use this method to get the number of members in a chat. Returns Int on success.
 func (bot *Bot) GetChatMembersCount(gcmcReq *GetChatMembersCountRequest) (int, error) {
-	var target int
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.getChatMembersCount,
-		gcmcReq,
-		&target,
-	); postErr != nil {
-		return 0, postErr
-	}
-	return target, nil
+	return call[int](bot, MethodGetChatMembersCount, gcmcReq)
 }
 
-type GetChatMemberRequest struct{}
+type GetChatMemberRequest struct {
+	ChatId interface{} `json:"chat_id"` // Unique identifier for the target chat or username of the target supergroup or channel (in the format @channelusername)
+	UserId int         `json:"user_id"` // Unique identifier of the target user
+}
 
 // Use this method to get information about a member of a chat. Returns a ChatMember object on success.
 func (bot *Bot) GetChatMember(gcmemReq *GetChatMemberRequest) (*en.ChatMember, error) {
-	var target en.ChatMember
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.getChatMember,
-		gcmemReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.ChatMember](bot, MethodGetChatMember, gcmemReq)
 }
 
 type SetChatStickerSetRequest struct {
@@ -772,14 +624,7 @@ type SetChatStickerSetRequest struct {
 // this to work and must have the appropriate admin rights. Use the field can_set_sticker_set optionally returned in
 // getChat requests to check if the bot can use this method. Returns True on success.
 func (bot *Bot) SetChatStickerSet(scstReq *SetChatStickerSetRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.setChatStickerSet,
-		scstReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodSetChatStickerSet, scstReq)
 }
 
 type DeleteChatStickerSetRequest struct {
@@ -790,35 +635,59 @@ type DeleteChatStickerSetRequest struct {
 // this to work and must have the appropriate admin rights. Use the field can_set_sticker_set optionally returned in
 // getChat requests to check if the bot can use this method. Returns True on success.
 func (bot *Bot) DeleteChatStickerSet(dcstReq *DeleteChatStickerSetRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.deleteChatStickerSet,
-		dcstReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
-}
-
-//type EditMessageTextRequest struct{}
-//
-//// Use this method to edit text and game messages. On success, if edited message is sent by the bot, the edited
-//// Message is returned, otherwise True is returned.
-//func (bot *Bot) EditMessageText(emtReq *EditMessageTextRequest) (zzz, error) {}
-//
-//type EditMessageCaptionRequest struct{}
-//
-//// Use this method to edit captions of messages. On success, if edited message is sent by the bot, the edited
-//// Message is returned, otherwise True is returned.
-//func (bot *Bot) EditMessageCaption(emcReq *EditMessageCaptionRequest) (zzz, error) {}
-//
-//type EditMessageMediaRequest struct{}
-//
-//// Use this method to edit animation, audio, document, photo, or video messages. If a message is a part of a message
-//// album, then it can be edited only to a photo or a video. Otherwise, message type can be changed arbitrarily. When
-//// inline message is edited, new file can't be uploaded. Use previously uploaded file via its file_id or specify a URL.
-//// On success, if the edited message was sent by the bot, the edited Message is returned, otherwise True is returned.
-//func (bot *Bot) EditMessageMedia(emmReq *EditMessageMediaRequest) (zzz, error) {}
+	return call[bool](bot, MethodDeleteChatStickerSet, dcstReq)
+}
+
+type EditMessageTextRequest struct {
+	ChatId                interface{}              `json:"chat_id,omitempty"`                  // Optional 	Required if inline_message_id is not specified. Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	MessageId             int                      `json:"message_id,omitempty"`               // Optional 	Required if inline_message_id is not specified. Identifier of the sent message
+	InlineMessageId       string                   `json:"inline_message_id,omitempty"`        // Optional 	Required if chat_id and message_id are not specified. Identifier of the inline message
+	Text                  string                   `json:"text"`                               // New text of the message
+	ParseMode             string                   `json:"parse_mode,omitempty"`               // Optional 	Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in your bot's message.
+	DisableWebPagePreview bool                     `json:"disable_web_page_preview,omitempty"` // Optional 	Disables link previews for links in this message
+	ReplyMarkup           *en.InlineKeyboardMarkup `json:"reply_markup,omitempty"`             // Optional 	A JSON-serialized object for an inline keyboard.
+}
+
+// Use this method to edit text and game messages. On success, if edited message is sent by the bot, the edited
+// Message is returned, otherwise True is returned (nil Message is returned in that case).
+func (bot *Bot) EditMessageText(emtReq *EditMessageTextRequest) (*en.Message, error) {
+	result, errCall := call[editResult](bot, MethodEditMessageText, emtReq)
+	return result.message, errCall
+}
+
+type EditMessageCaptionRequest struct {
+	ChatId          interface{}              `json:"chat_id,omitempty"`           // Optional 	Required if inline_message_id is not specified. Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	MessageId       int                      `json:"message_id,omitempty"`        // Optional 	Required if inline_message_id is not specified. Identifier of the sent message
+	InlineMessageId string                   `json:"inline_message_id,omitempty"` // Optional 	Required if chat_id and message_id are not specified. Identifier of the inline message
+	Caption         string                   `json:"caption,omitempty"`           // Optional 	New caption of the message
+	ParseMode       string                   `json:"parse_mode,omitempty"`        // Optional 	Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs in the media caption.
+	ReplyMarkup     *en.InlineKeyboardMarkup `json:"reply_markup,omitempty"`      // Optional 	A JSON-serialized object for an inline keyboard.
+}
+
+// Use this method to edit captions of messages. On success, if edited message is sent by the bot, the edited
+// Message is returned, otherwise True is returned (nil Message is returned in that case).
+func (bot *Bot) EditMessageCaption(emcReq *EditMessageCaptionRequest) (*en.Message, error) {
+	result, errCall := call[editResult](bot, MethodEditMessageCaption, emcReq)
+	return result.message, errCall
+}
+
+type EditMessageMediaRequest struct {
+	ChatId          interface{}              `json:"chat_id,omitempty"`           // Optional 	Required if inline_message_id is not specified. Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	MessageId       int                      `json:"message_id,omitempty"`        // Optional 	Required if inline_message_id is not specified. Identifier of the sent message
+	InlineMessageId string                   `json:"inline_message_id,omitempty"` // Optional 	Required if chat_id and message_id are not specified. Identifier of the inline message
+	Media           *en.InputMedia           `json:"media"`                       // A JSON-serialized object for a new media content of the message
+	ReplyMarkup     *en.InlineKeyboardMarkup `json:"reply_markup,omitempty"`      // Optional 	A JSON-serialized object for a new inline keyboard.
+}
+
+// Use this method to edit animation, audio, document, photo, or video messages. If a message is a part of a message
+// album, then it can be edited only to a photo or a video. Otherwise, message type can be changed arbitrarily. When
+// inline message is edited, new file can't be uploaded. Use previously uploaded file via its file_id or specify a URL.
+// On success, if the edited message was sent by the bot, the edited Message is returned, otherwise True is returned
+// (nil Message is returned in that case).
+func (bot *Bot) EditMessageMedia(emmReq *EditMessageMediaRequest) (*en.Message, error) {
+	result, errCall := call[editResult](bot, MethodEditMessageMedia, emmReq)
+	return result.message, errCall
+}
 
 type DeleteMessageRequest struct {
 	ChatId    interface{} `json:"chat_id"`    // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
@@ -834,14 +703,7 @@ type DeleteMessageRequest struct {
 // - If the bot has can_delete_messages permission in a supergroup or a channel, it can delete any message there.
 // Returns True on success.
 func (bot *Bot) DeleteMessage(dmReq *DeleteMessageRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.deleteMessage,
-		dmReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodDeleteMessage, dmReq)
 }
 
 type GetStickerSetRequest struct {
@@ -850,50 +712,47 @@ type GetStickerSetRequest struct {
 
 // Use this method to get a sticker set. On success, a StickerSet object is returned.
 func (bot *Bot) GetStickerSet(gstsReq *GetStickerSetRequest) (*en.StickerSet, error) {
-	var target en.StickerSet
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.getStickerSet,
-		gstsReq,
-		&target,
-	); postErr != nil {
-		return nil, postErr
-	}
-	return &target, nil
+	return call[*en.StickerSet](bot, MethodGetStickerSet, gstsReq)
+}
+
+type UploadStickerFileRequest struct {
+	UserId     int           `json:"user_id"`     // User identifier of sticker file owner
+	PngSticker *en.InputFile `json:"png_sticker"` // Png image with the sticker, must be up to 512 kilobytes in size, dimensions must not exceed 512px, and either width or height must be exactly 512px. More info on Sending Files »
 }
 
-//type UploadStickerFileRequest struct{}
-//
-//// Use this method to upload a .png file with a sticker for later use in createNewStickerSet and addStickerToSet
-//// methods (can be used multiple times). Returns the uploaded File on success.
-//func (bot *Bot) UploadStickerFile(ustfReq *UploadStickerFileRequest) (File, error) {}
+// Use this method to upload a .png file with a sticker for later use in createNewStickerSet and addStickerToSet
+// methods (can be used multiple times). Returns the uploaded File on success.
+func (bot *Bot) UploadStickerFile(ustfReq *UploadStickerFileRequest) (*en.File, error) {
+	return call[*en.File](bot, MethodUploadStickerFile, ustfReq)
+}
 
-type CreateNewStickerSetRequest struct{}
+type CreateNewStickerSetRequest struct {
+	UserId        int              `json:"user_id"`                  // User identifier of created sticker set owner
+	Name          string           `json:"name"`                     // Short name of sticker set, to be used in t.me/addstickers/ URLs (e.g., animals). Can contain only english letters, digits and underscores. Must begin with a letter, can't contain consecutive underscores and must end in “_by_<bot username>”. <bot_username> is case insensitive. 1-64 characters.
+	Title         string           `json:"title"`                    // Sticker set title, 1-64 characters
+	PngSticker    *en.InputFile    `json:"png_sticker"`              // Png image with the sticker, must be up to 512 kilobytes in size, dimensions must not exceed 512px, and either width or height must be exactly 512px. Pass a file_id as a String to send a file that already exists on the Telegram servers, pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
+	Emojis        string           `json:"emojis"`                   // One or more emoji corresponding to the sticker
+	ContainsMasks bool             `json:"contains_masks,omitempty"` // Optional 	Pass True, if a set of mask stickers should be created
+	MaskPosition  *en.MaskPosition `json:"mask_position,omitempty"`  // Optional 	A JSON-serialized object for position where the mask should be placed on faces
+}
 
 // Use this method to create new sticker set owned by a user. The bot will be able to edit the created sticker set.
 // Returns True on success.
 func (bot *Bot) CreateNewStickerSet(cnstsReq *CreateNewStickerSetRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.createNewStickerSet,
-		cnstsReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodCreateNewStickerSet, cnstsReq)
 }
 
-type AddStickerToSetRequest struct{}
+type AddStickerToSetRequest struct {
+	UserId       int              `json:"user_id"`                 // User identifier of sticker set owner
+	Name         string           `json:"name"`                    // Sticker set name
+	PngSticker   *en.InputFile    `json:"png_sticker"`             // Png image with the sticker, must be up to 512 kilobytes in size, dimensions must not exceed 512px, and either width or height must be exactly 512px. Pass a file_id as a String to send a file that already exists on the Telegram servers, pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload a new one using multipart/form-data. More info on Sending Files »
+	Emojis       string           `json:"emojis"`                  // One or more emoji corresponding to the sticker
+	MaskPosition *en.MaskPosition `json:"mask_position,omitempty"` // Optional 	A JSON-serialized object for position where the mask should be placed on faces
+}
 
 // Use this method to add a new sticker to a set created by the bot. Returns True on success.
 func (bot *Bot) AddStickerToSet(asttsReq *AddStickerToSetRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.addStickerToSet,
-		asttsReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodAddStickerToSet, asttsReq)
 }
 
 type SetStickerPositionInSetRequest struct {
@@ -903,14 +762,7 @@ type SetStickerPositionInSetRequest struct {
 
 // Use this method to move a sticker in a set created by the bot to a specific position . Returns True on success.
 func (bot *Bot) SetStickerPositionInSet(sstpisReq *SetStickerPositionInSetRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.setStickerPositionInSet,
-		sstpisReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
-	}
-	return true, nil
+	return call[bool](bot, MethodSetStickerPositionInSet, sstpisReq)
 }
 
 type DeleteStickerFromSetRequest struct {
@@ -919,12 +771,181 @@ type DeleteStickerFromSetRequest struct {
 
 // Use this method to delete a sticker from a set created by the bot. Returns True on success.
 func (bot *Bot) DeleteStickerFromSet(dstfsReq *DeleteStickerFromSetRequest) (bool, error) {
-	if postErr := bot.requestGate.makePostRequest(
-		bot.urls.deleteStickerFromSet,
-		dstfsReq,
-		nil,
-	); postErr != nil {
-		return false, postErr
+	return call[bool](bot, MethodDeleteStickerFromSet, dstfsReq)
+}
+
+// Use this method to specify a url and receive incoming updates via an outgoing webhook. Whenever there is an update
+// for the bot, Telegram will send an HTTPS POST request to the specified url, containing a JSON-serialized Update.
+// In case of an unsuccessful request, Telegram will give up after a reasonable amount of attempts. Returns True on success.
+type SetWebhookRequest struct {
+	Url            string        `json:"url"`                       // HTTPS url to send updates to. Use an empty string to remove webhook integration
+	Certificate    *en.InputFile `json:"certificate,omitempty"`     // Optional 	Upload your public key certificate so that the root certificate in use can be checked
+	MaxConnections int           `json:"max_connections,omitempty"` // Optional 	Maximum allowed number of simultaneous HTTPS connections to the webhook for update delivery, 1-100. Defaults to 40.
+	AllowedUpdates []string      `json:"allowed_updates,omitempty"` // Optional 	List the types of updates you want your bot to receive. If not specified, update types are generated from BotCallbacksContainer.
+	SecretToken    string        `json:"secret_token,omitempty"`    // Optional 	A secret token to be sent in a header “X-Telegram-Bot-Api-Secret-Token” in every webhook request, 1-256 characters. Defaults to Config.WebhookSecretToken.
+}
+
+func (bot *Bot) SetWebhook(swReq *SetWebhookRequest) (bool, error) {
+	// defaults are filled in a copy, the request of the caller is left as is
+	var req SetWebhookRequest
+	if swReq != nil {
+		req = *swReq
+	}
+	// deliver the same update types as GetUpdates loop does
+	if req.AllowedUpdates == nil {
+		req.AllowedUpdates = bot.callbacks.allowedUpdatesList()
+	}
+	if req.SecretToken == "" {
+		req.SecretToken = bot.config.WebhookSecretToken
 	}
-	return true, nil
+	return call[bool](bot, MethodSetWebhook, &req)
+}
+
+type DeleteWebhookRequest struct {
+	DropPendingUpdates bool `json:"drop_pending_updates,omitempty"` // Optional 	Pass True to drop all pending updates
+}
+
+// Use this method to remove webhook integration if you decide to switch back to getUpdates. Returns True on success.
+func (bot *Bot) DeleteWebhook(dwReq *DeleteWebhookRequest) (bool, error) {
+	return call[bool](bot, MethodDeleteWebhook, dwReq)
+}
+
+// Use this method to get current webhook status. Requires no parameters. On success, returns a WebhookInfo object.
+// If the bot is using getUpdates, will return an object with the url field empty.
+func (bot *Bot) GetWebhookInfo() (*en.WebhookInfo, error) {
+	return call[*en.WebhookInfo](bot, MethodGetWebhookInfo, nil)
+}
+
+// Use this method to send invoices. On success, the sent Message is returned.
+type SendInvoiceRequest struct {
+	ChatId                    int                      `json:"chat_id"`                                 // Unique identifier for the target private chat
+	Title                     string                   `json:"title"`                                   // Product name, 1-32 characters
+	Description               string                   `json:"description"`                             // Product description, 1-255 characters
+	Payload                   string                   `json:"payload"`                                 // Bot-defined invoice payload, 1-128 bytes. This will not be displayed to the user, use for your internal processes.
+	ProviderToken             string                   `json:"provider_token"`                          // Payments provider token, obtained via Botfather
+	StartParameter            string                   `json:"start_parameter"`                         // Unique deep-linking parameter that can be used to generate this invoice when used as a start parameter
+	Currency                  string                   `json:"currency"`                                // Three-letter ISO 4217 currency code
+	Prices                    []en.LabeledPrice        `json:"prices"`                                  // Price breakdown, a list of components (e.g. product price, tax, discount, delivery cost, delivery tax, bonus, etc.)
+	ProviderData              string                   `json:"provider_data,omitempty"`                 // Optional 	JSON-encoded data about the invoice, which will be shared with the payment provider. A detailed description of required fields should be provided by the payment provider.
+	PhotoUrl                  string                   `json:"photo_url,omitempty"`                     // Optional 	URL of the product photo for the invoice. Can be a photo of the goods or a marketing image for a service. People like it better when they see what they are paying for.
+	PhotoSize                 int                      `json:"photo_size,omitempty"`                    // Optional 	Photo size
+	PhotoWidth                int                      `json:"photo_width,omitempty"`                   // Optional 	Photo width
+	PhotoHeight               int                      `json:"photo_height,omitempty"`                  // Optional 	Photo height
+	NeedName                  bool                     `json:"need_name,omitempty"`                     // Optional 	Pass True, if you require the user's full name to complete the order
+	NeedPhoneNumber           bool                     `json:"need_phone_number,omitempty"`             // Optional 	Pass True, if you require the user's phone number to complete the order
+	NeedEmail                 bool                     `json:"need_email,omitempty"`                    // Optional 	Pass True, if you require the user's email address to complete the order
+	NeedShippingAddress       bool                     `json:"need_shipping_address,omitempty"`         // Optional 	Pass True, if you require the user's shipping address to complete the order
+	SendPhoneNumberToProvider bool                     `json:"send_phone_number_to_provider,omitempty"` // Optional 	Pass True, if user's phone number should be sent to provider
+	SendEmailToProvider       bool                     `json:"send_email_to_provider,omitempty"`        // Optional 	Pass True, if user's email address should be sent to provider
+	IsFlexible                bool                     `json:"is_flexible,omitempty"`                   // Optional 	Pass True, if the final price depends on the shipping method
+	DisableNotification       bool                     `json:"disable_notification,omitempty"`          // Optional 	Sends the message silently. Users will receive a notification with no sound.
+	ReplyToMessageId          int                      `json:"reply_to_message_id,omitempty"`           // Optional 	If the message is a reply, ID of the original message
+	ReplyMarkup               *en.InlineKeyboardMarkup `json:"reply_markup,omitempty"`                  // Optional 	A JSON-serialized object for an inline keyboard. If empty, one 'Pay total price' button will be shown. If not empty, the first button must be a Pay button.
+}
+
+func (bot *Bot) SendInvoice(siReq *SendInvoiceRequest) (*en.Message, error) {
+	return call[*en.Message](bot, MethodSendInvoice, siReq)
+}
+
+// If you sent an invoice requesting a shipping address and the parameter is_flexible was specified, the Bot API will
+// send an Update with a shipping_query field to the bot. Use this method to reply to shipping queries.
+// On success, True is returned.
+type AnswerShippingQueryRequest struct {
+	ShippingQueryId string              `json:"shipping_query_id"`          // Unique identifier for the query to be answered
+	Ok              bool                `json:"ok"`                         // Specify True if delivery to the specified address is possible and False if there are any problems (for example, if delivery to the specified address is not possible)
+	ShippingOptions []en.ShippingOption `json:"shipping_options,omitempty"` // Optional 	Required if ok is True. A JSON-serialized array of available shipping options.
+	ErrorMessage    string              `json:"error_message,omitempty"`    // Optional 	Required if ok is False. Error message in human readable form that explains why it is impossible to complete the order (e.g. "Sorry, delivery to your desired address is unavailable'). Telegram will display this message to the user.
+}
+
+func (bot *Bot) AnswerShippingQuery(asqReq *AnswerShippingQueryRequest) (bool, error) {
+	return call[bool](bot, MethodAnswerShippingQuery, asqReq)
+}
+
+// Once the user has confirmed their payment and shipping details, the Bot API sends the final confirmation in the form
+// of an Update with the field pre_checkout_query. Use this method to respond to such pre-checkout queries.
+// On success, True is returned. Note: The Bot API must receive an answer within 10 seconds after the pre-checkout
+// query was sent.
+type AnswerPreCheckoutQueryRequest struct {
+	PreCheckoutQueryId string `json:"pre_checkout_query_id"`   // Unique identifier for the query to be answered
+	Ok                 bool   `json:"ok"`                      // Specify True if everything is alright (goods are available, etc.) and the bot is ready to proceed with the order. Use False if there are any problems.
+	ErrorMessage       string `json:"error_message,omitempty"` // Optional 	Required if ok is False. Error message in human readable form that explains the reason for failure to proceed with the checkout (e.g. "Sorry, somebody just bought the last of our amazing black T-shirts while you were busy filling out your payment details. Please choose a different color or garment!"). Telegram will display this message to the user.
+}
+
+func (bot *Bot) AnswerPreCheckoutQuery(apcqReq *AnswerPreCheckoutQueryRequest) (bool, error) {
+	return call[bool](bot, MethodAnswerPreCheckoutQuery, apcqReq)
+}
+
+// Informs a user that some of the Telegram Passport elements they provided contains errors. The user will not be able
+// to re-submit their Passport to you until the errors are fixed (the contents of the field for which you returned
+// the error must change). Returns True on success.
+// Use this if the data submitted by the user doesn't satisfy the standards your service requires for any reason.
+// For example, if a birthday date seems invalid, a submitted document is blurry, a scan shows evidence of tampering,
+// etc. Supply some details in the error message to make sure the user knows how to correct the issues.
+type SetPassportDataErrorsRequest struct {
+	UserId int                       `json:"user_id"` // User identifier
+	Errors []en.PassportElementError `json:"errors"`  // A JSON-serialized array describing the errors
+}
+
+func (bot *Bot) SetPassportDataErrors(spdeReq *SetPassportDataErrorsRequest) (bool, error) {
+	return call[bool](bot, MethodSetPassportDataErrors, spdeReq)
+}
+
+// Use this method to send a game. On success, the sent Message is returned.
+type SendGameRequest struct {
+	ChatId              int                      `json:"chat_id"`                        // Unique identifier for the target chat
+	GameShortName       string                   `json:"game_short_name"`                // Short name of the game, serves as the unique identifier for the game. Set up your games via Botfather.
+	DisableNotification bool                     `json:"disable_notification,omitempty"` // Optional 	Sends the message silently. Users will receive a notification with no sound.
+	ReplyToMessageId    int                      `json:"reply_to_message_id,omitempty"`  // Optional 	If the message is a reply, ID of the original message
+	ReplyMarkup         *en.InlineKeyboardMarkup `json:"reply_markup,omitempty"`         // Optional 	A JSON-serialized object for an inline keyboard. If empty, one ‘Play game_title’ button will be shown. If not empty, the first button must launch the game.
+}
+
+func (bot *Bot) SendGame(sgReq *SendGameRequest) (*en.Message, error) {
+	return call[*en.Message](bot, MethodSendGame, sgReq)
+}
+
+// Use this method to set the score of the specified user in a game. On success, if the message was sent by the bot,
+// returns the edited Message, otherwise returns True. Returns an error, if the new score is not greater than
+// the user's current score in the chat and force is False.
+type SetGameScoreRequest struct {
+	UserId             int    `json:"user_id"`                        // User identifier
+	Score              int    `json:"score"`                          // New score, must be non-negative
+	Force              bool   `json:"force,omitempty"`                // Optional 	Pass True, if the high score is allowed to decrease. This can be useful when fixing mistakes or banning cheaters
+	DisableEditMessage bool   `json:"disable_edit_message,omitempty"` // Optional 	Pass True, if the game message should not be automatically edited to include the current scoreboard
+	ChatId             int    `json:"chat_id,omitempty"`              // Optional 	Required if inline_message_id is not specified. Unique identifier for the target chat
+	MessageId          int    `json:"message_id,omitempty"`           // Optional 	Required if inline_message_id is not specified. Identifier of the sent message
+	InlineMessageId    string `json:"inline_message_id,omitempty"`    // Optional 	Required if chat_id and message_id are not specified. Identifier of the inline message
+}
+
+// Returns nil Message if score was set for inline message.
+func (bot *Bot) SetGameScore(sgsReq *SetGameScoreRequest) (*en.Message, error) {
+	result, errCall := call[editResult](bot, MethodSetGameScore, sgsReq)
+	return result.message, errCall
+}
+
+// Use this method to get data for high score tables. Will return the score of the specified user and several
+// of his neighbors in a game. On success, returns an Array of GameHighScore objects.
+type GetGameHighScoresRequest struct {
+	UserId          int    `json:"user_id"`                     // Target user id
+	ChatId          int    `json:"chat_id,omitempty"`           // Optional 	Required if inline_message_id is not specified. Unique identifier for the target chat
+	MessageId       int    `json:"message_id,omitempty"`        // Optional 	Required if inline_message_id is not specified. Identifier of the sent message
+	InlineMessageId string `json:"inline_message_id,omitempty"` // Optional 	Required if chat_id and message_id are not specified. Identifier of the inline message
+}
+
+func (bot *Bot) GetGameHighScores(gghsReq *GetGameHighScoresRequest) ([]en.GameHighScore, error) {
+	return call[[]en.GameHighScore](bot, MethodGetGameHighScores, gghsReq)
+}
+
+// Use this method to change the list of the bot's commands. Returns True on success.
+type SetMyCommandsRequest struct {
+	Commands []en.BotCommand `json:"commands"` // A JSON-serialized list of bot commands to be set as the list of the bot's commands. At most 100 commands can be specified.
+}
+
+func (bot *Bot) SetMyCommands(smcReq *SetMyCommandsRequest) (bool, error) {
+	return call[bool](bot, MethodSetMyCommands, smcReq)
+}
+
+// Use this method to get the current list of the bot's commands. Requires no parameters.
+// Returns Array of BotCommand on success.
+func (bot *Bot) GetMyCommands() ([]en.BotCommand, error) {
+	return call[[]en.BotCommand](bot, MethodGetMyCommands, nil)
 }
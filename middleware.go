@@ -0,0 +1,77 @@
+package botan
+
+import (
+	"context"
+	"time"
+
+	en "github.com/isvinogradov/botan/entities"
+)
+
+// Processes an update. The innermost Handler routes the update to the respective callback
+// of BotCallbacksContainer.
+type Handler func(bot *Bot, update *en.Update) error
+
+// Wraps a Handler to run code before and after it, to change the bot passed further (e.g. with WithContext)
+// or to stop processing by not calling next.
+type Middleware func(next Handler) Handler
+
+// Wraps handler so that middleware[0] is called first
+func chainMiddleware(middleware []Middleware, handler Handler) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// Middleware cancelling API requests made while processing an update after timeout
+func TimeoutMiddleware(timeout time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(bot *Bot, update *en.Update) error {
+			ctx, cancel := context.WithTimeout(bot.context(), timeout)
+			defer cancel()
+			return next(bot.WithContext(ctx), update)
+		}
+	}
+}
+
+// Middleware passing further only updates sent by the listed users. Updates without a sender (channel posts
+// and polls) are dropped too.
+func AllowUsersMiddleware(userIds ...int) Middleware {
+	allowed := make(map[int]bool, len(userIds))
+	for _, id := range userIds {
+		allowed[id] = true
+	}
+	return func(next Handler) Handler {
+		return func(bot *Bot, update *en.Update) error {
+			if sender := UpdateSender(update); sender == nil || !allowed[sender.Id] {
+				return nil
+			}
+			return next(bot, update)
+		}
+	}
+}
+
+// Returns the user who caused the update or nil if there's none
+func UpdateSender(update *en.Update) *en.User {
+	switch {
+	case update.Message != nil:
+		return update.Message.From
+	case update.EditedMessage != nil:
+		return update.EditedMessage.From
+	case update.ChannelPost != nil:
+		return update.ChannelPost.From
+	case update.EditedChannelPost != nil:
+		return update.EditedChannelPost.From
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.Sender
+	case update.InlineQuery != nil:
+		return update.InlineQuery.Sender
+	case update.ChosenInlineResult != nil:
+		return update.ChosenInlineResult.Sender
+	case update.ShippingQuery != nil:
+		return update.ShippingQuery.From
+	case update.PreCheckoutQuery != nil:
+		return update.PreCheckoutQuery.From
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+package botan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+var inputFileType = reflect.TypeOf((*entities.InputFile)(nil))
+
+// Body of a request to Telegram API. Payload is serialized to JSON unless it contains files to upload:
+// then it's streamed as multipart/form-data.
+type requestBody struct {
+	parts       []multipartPart // form fields and files of multipart requests
+	jsonPayload []byte          // nil for multipart requests
+	replayable  bool            // false if body contains io.Reader uploads which can't be read twice
+}
+
+// Form field with JSON-serialized value or file to upload
+type multipartPart struct {
+	name  string
+	value string
+	file  *entities.InputFile
+}
+
+func newRequestBody(payload interface{}) (*requestBody, error) {
+	topLevel, nested := collectUploads(payload)
+	if len(topLevel) == 0 && len(nested) == 0 {
+		// marshal payload to JSON
+		jsonPayload, marshalErr := json.Marshal(payload)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		return &requestBody{jsonPayload: jsonPayload, replayable: true}, nil
+	}
+
+	body := requestBody{replayable: true}
+	for _, f := range append(topLevel, nested...) {
+		if f.Reader != nil {
+			body.replayable = false
+		}
+	}
+	// nested files are referenced from JSON fields as attach://<name>; a file used several times is uploaded once
+	attachNames := make(map[*entities.InputFile]string)
+	var attached []multipartPart
+	for _, f := range nested {
+		if _, ok := attachNames[f]; !ok {
+			attachNames[f] = fmt.Sprintf("file%d", len(attached))
+			attached = append(attached, multipartPart{name: attachNames[f], file: f})
+		}
+	}
+	// fields are serialized before sending, so invalid values are reported before anything is sent
+	parts, errParts := multipartParts(withAttachRefs(payload, attachNames))
+	if errParts != nil {
+		return nil, errParts
+	}
+	body.parts = append(parts, attached...)
+	return &body, nil
+}
+
+// Returns reader with the request body and its content type. Every call produces a new reader.
+func (rb *requestBody) open() (io.ReadCloser, string) {
+	if rb.jsonPayload != nil {
+		return io.NopCloser(bytes.NewReader(rb.jsonPayload)), "application/json"
+	}
+
+	// files are written to the pipe while HTTP client reads from it, so they are never loaded to memory
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		errWrite := writeMultipart(mw, rb.parts)
+		if errWrite == nil {
+			errWrite = mw.Close()
+		}
+		pw.CloseWithError(errWrite)
+	}()
+	return pr, mw.FormDataContentType()
+}
+
+// Converts all non-empty fields of request struct to form fields and top-level uploads to files
+func multipartParts(payload interface{}) ([]multipartPart, error) {
+	v := reflect.Indirect(reflect.ValueOf(payload))
+	t := v.Type()
+	var parts []multipartPart
+	for i := 0; i < t.NumField(); i++ {
+		name, omitEmpty := jsonFieldName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		fv := v.Field(i)
+
+		if f, ok := fv.Interface().(*entities.InputFile); ok {
+			if f == nil {
+				continue
+			}
+			if f.IsUpload() {
+				parts = append(parts, multipartPart{name: name, file: f})
+				continue
+			}
+		}
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		// complex values are sent as JSON-serialized strings, plain values as is
+		fieldJson, errMarshal := json.Marshal(fv.Interface())
+		if errMarshal != nil {
+			return nil, errMarshal
+		}
+		fieldValue := string(fieldJson)
+		if strings.HasPrefix(fieldValue, "\"") {
+			if errUnquote := json.Unmarshal(fieldJson, &fieldValue); errUnquote != nil {
+				return nil, errUnquote
+			}
+		}
+		parts = append(parts, multipartPart{name: name, value: fieldValue})
+	}
+	return parts, nil
+}
+
+func writeMultipart(mw *multipart.Writer, parts []multipartPart) error {
+	for _, part := range parts {
+		if part.file != nil {
+			if errFile := writeFilePart(mw, part.name, part.file); errFile != nil {
+				return errFile
+			}
+			continue
+		}
+		if errField := mw.WriteField(part.name, part.value); errField != nil {
+			return errField
+		}
+	}
+	return nil
+}
+
+func writeFilePart(mw *multipart.Writer, fieldName string, f *entities.InputFile) error {
+	part, errPart := mw.CreateFormFile(fieldName, f.FileName())
+	if errPart != nil {
+		return errPart
+	}
+	if f.Reader != nil {
+		_, errCopy := io.Copy(part, f.Reader)
+		return errCopy
+	}
+
+	file, errOpen := os.Open(f.Path)
+	if errOpen != nil {
+		return errOpen
+	}
+	defer file.Close()
+	_, errCopy := io.Copy(part, file)
+	return errCopy
+}
+
+// Finds InputFile uploads in request struct. Uploads in the fields of the struct itself are returned as
+// topLevel; uploads inside other values (e.g. InputMedia in slices) are returned as nested.
+func collectUploads(payload interface{}) (topLevel []*entities.InputFile, nested []*entities.InputFile) {
+	v := reflect.ValueOf(payload)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath != "" {
+			continue // unexported
+		}
+		fv := v.Field(i)
+		if fv.Type() == inputFileType {
+			if f := fv.Interface().(*entities.InputFile); f != nil && f.IsUpload() {
+				topLevel = append(topLevel, f)
+			}
+			continue
+		}
+		nested = appendNestedUploads(nested, fv, 0)
+	}
+	return topLevel, nested
+}
+
+const maxUploadSearchDepth = 8
+
+func appendNestedUploads(found []*entities.InputFile, v reflect.Value, depth int) []*entities.InputFile {
+	if depth > maxUploadSearchDepth || !v.IsValid() {
+		return found
+	}
+	if v.Type() == inputFileType {
+		if f := v.Interface().(*entities.InputFile); f != nil && f.IsUpload() {
+			found = append(found, f)
+		}
+		return found
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			found = appendNestedUploads(found, v.Elem(), depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			found = appendNestedUploads(found, v.Index(i), depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				found = appendNestedUploads(found, v.Field(i), depth+1)
+			}
+		}
+	}
+	return found
+}
+
+// Returns copy of request struct in which nested uploads are replaced with attach://<name> references. Values of
+// the caller are never modified, so the same InputFile may be sent by concurrent requests.
+func withAttachRefs(payload interface{}, names map[*entities.InputFile]string) interface{} {
+	if len(names) == 0 {
+		return payload
+	}
+	v := reflect.ValueOf(payload)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return payload
+	}
+	copied := reflect.New(v.Elem().Type())
+	copied.Elem().Set(v.Elem())
+	for i := 0; i < copied.Elem().NumField(); i++ {
+		if v.Elem().Type().Field(i).PkgPath != "" || v.Elem().Field(i).Type() == inputFileType {
+			continue // unexported or top-level upload
+		}
+		copied.Elem().Field(i).Set(replaceUploads(v.Elem().Field(i), names, 0))
+	}
+	return copied.Interface()
+}
+
+// Returns v with uploads found in names replaced; containers on the way to them are copied
+func replaceUploads(v reflect.Value, names map[*entities.InputFile]string, depth int) reflect.Value {
+	if depth > maxUploadSearchDepth || !v.IsValid() {
+		return v
+	}
+	if v.Type() == inputFileType {
+		if name, ok := names[v.Interface().(*entities.InputFile)]; ok {
+			return reflect.ValueOf(entities.InputFileFromId("attach://" + name))
+		}
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(replaceUploads(v.Elem(), names, depth+1))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(replaceUploads(v.Elem(), names, depth+1))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(replaceUploads(v.Index(i), names, depth+1))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(replaceUploads(v.Index(i), names, depth+1))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v) // keeps unexported fields
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				copied.Field(i).Set(replaceUploads(v.Field(i), names, depth+1))
+			}
+		}
+		return copied
+	}
+	return v
+}
+
+// Returns JSON name of the struct field and whether it has omitempty option
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
@@ -0,0 +1,115 @@
+package botan
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+// Reads multipart request body to map of field values and map of file contents
+func readMultipart(rb *requestBody) (map[string]string, map[string]string, error) {
+	body, contentType := rb.open()
+	defer body.Close()
+	_, params, errType := mime.ParseMediaType(contentType)
+	if errType != nil {
+		return nil, nil, errType
+	}
+	fields, files := make(map[string]string), make(map[string]string)
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, errPart := mr.NextPart()
+		if errPart == io.EOF {
+			return fields, files, nil
+		}
+		if errPart != nil {
+			return nil, nil, errPart
+		}
+		content, errRead := io.ReadAll(part)
+		if errRead != nil {
+			return nil, nil, errRead
+		}
+		if part.FileName() != "" {
+			files[part.FormName()] = string(content)
+		} else {
+			fields[part.FormName()] = string(content)
+		}
+	}
+}
+
+func TestNestedUploadsDontModifyRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "photo.jpg")
+	if errWrite := os.WriteFile(path, []byte("jpeg"), 0o600); errWrite != nil {
+		t.Fatal(errWrite)
+	}
+	photo := entities.InputFileFromPath(path)
+	req := &SendMediaGroupRequest{
+		ChatId: 42,
+		Media: []entities.InputMedia{
+			{ExactType: &entities.InputMediaPhoto{Type: "photo", Media: photo}},
+			{ExactType: entities.InputMediaPhoto{Type: "photo", Media: photo}},
+			{ExactType: entities.InputMediaPhoto{Type: "photo", Media: entities.InputFileFromId("abc")}},
+		},
+	}
+	before := *photo
+
+	rb, errBody := newRequestBody(req)
+	if errBody != nil {
+		t.Fatal(errBody)
+	}
+	fields, files, errRead := readMultipart(rb)
+	if errRead != nil {
+		t.Fatal(errRead)
+	}
+
+	wantMedia := `[{"type":"photo","media":"attach://file0"},{"type":"photo","media":"attach://file0"},{"type":"photo","media":"abc"}]`
+	if fields["media"] != wantMedia {
+		t.Errorf("media field %s, want %s", fields["media"], wantMedia)
+	}
+	if !reflect.DeepEqual(files, map[string]string{"file0": "jpeg"}) {
+		t.Errorf("uploaded files %v", files)
+	}
+	if *photo != before || req.Media[0].ExactType.(*entities.InputMediaPhoto).Media != photo {
+		t.Error("request was modified")
+	}
+}
+
+func TestConcurrentRequestsShareInputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "photo.jpg")
+	if errWrite := os.WriteFile(path, []byte("jpeg"), 0o600); errWrite != nil {
+		t.Fatal(errWrite)
+	}
+	photo := entities.InputFileFromPath(path)
+
+	var wg sync.WaitGroup
+	for chatId := 1; chatId <= 4; chatId++ {
+		wg.Add(1)
+		go func(chatId int) {
+			defer wg.Done()
+			rb, errBody := newRequestBody(&SendMediaGroupRequest{
+				ChatId: chatId,
+				Media: []entities.InputMedia{
+					{ExactType: entities.InputMediaPhoto{Type: "photo", Media: entities.InputFileFromId("abc")}},
+					{ExactType: entities.InputMediaPhoto{Type: "photo", Media: photo}},
+				},
+			})
+			if errBody != nil {
+				t.Error(errBody)
+				return
+			}
+			_, files, errRead := readMultipart(rb)
+			if errRead != nil {
+				t.Error(errRead)
+			} else if files["file0"] != "jpeg" {
+				t.Errorf("uploaded files %v", files)
+			}
+		}(chatId)
+	}
+	wg.Wait()
+}
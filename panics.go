@@ -0,0 +1,78 @@
+package botan
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+const defaultPanicRetries = 2
+
+// What to do with an update whose handler panicked
+type PanicPolicy int
+
+const (
+	PanicSkip  PanicPolicy = iota // report the panic to OnError and consider the update processed, so its offset is committed
+	PanicRetry                    // process the update again up to Config.PanicRetries times, then skip it
+)
+
+// PanicError is passed to OnError when a handler panics while processing an update
+type PanicError struct {
+	UpdateId int         // ID of the update being processed
+	Attempt  int         // zero-based attempt of processing the update
+	Value    interface{} // value passed to panic
+	Stack    []byte      // stack trace of the goroutine at the moment of panic
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("panic while processing update %d: %v", pe.UpdateId, pe.Value)
+}
+
+// Unwrap allows errors.Is and errors.As to look into panics with error values
+func (pe *PanicError) Unwrap() error {
+	if err, ok := pe.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Runs handler for the update according to panic policy. Panics are recovered and returned as *PanicError;
+// the last one is returned if all retries failed.
+func (bot *Bot) handleUpdate(update *entities.Update) error {
+	retries := 0
+	if bot.config.PanicPolicy == PanicRetry {
+		retries = bot.config.PanicRetries
+	}
+	for attempt := 0; ; attempt++ {
+		cbErr, panicErr := bot.recoverHandler(update, attempt)
+		if panicErr == nil {
+			return cbErr
+		}
+		if attempt >= retries {
+			return panicErr
+		}
+		bot.reportError(update, panicErr) // report every panic, not only the last one
+	}
+}
+
+// Logs error of update processing and passes it to OnError
+func (bot *Bot) reportError(update *entities.Update, err error) {
+	fields := append([]interface{}{"update_id", update.UpdateId}, errorFields(err)...)
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		fields = append(fields, "stack", string(panicErr.Stack))
+	}
+	bot.log.error("update processing failed", fields...)
+	bot.callbacks.OnError(err)
+}
+
+func (bot *Bot) recoverHandler(update *entities.Update, attempt int) (cbErr error, panicErr *PanicError) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr = &PanicError{UpdateId: update.UpdateId, Attempt: attempt, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return bot.handler(bot, update), nil
+}
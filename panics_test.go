@@ -0,0 +1,131 @@
+package botan_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+	"github.com/isvinogradov/botan/entities"
+)
+
+// Runs the bot until done is closed, then stops it and waits for Run to return
+func runUntil(t *testing.T, bot *botan.Bot, done <-chan struct{}) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		bot.Run(ctx)
+		close(stopped)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("updates not processed")
+	}
+	cancel()
+	<-stopped
+}
+
+func TestPanicSkip(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var errs []error
+	var offsets []int
+	handled := make(chan struct{})
+	bot := srv.Bot(t, &botan.BotCallbacksContainer{
+		OnMessage: func(bot *botan.Bot, msg *entities.Message) error {
+			if msg.Text == "poison" {
+				panic("poison update")
+			}
+			close(handled)
+			return nil
+		},
+		OnError: func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		},
+		OnSetNewOffset: func(offset int) {
+			mu.Lock()
+			offsets = append(offsets, offset)
+			mu.Unlock()
+		},
+	})
+	srv.QueueMessage(42, "poison")
+	srv.QueueMessage(42, "fine")
+	runUntil(t, bot, handled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 1 {
+		t.Fatalf("OnError got %v, want one panic", errs)
+	}
+	var panicErr *botan.PanicError
+	if !errors.As(errs[0], &panicErr) {
+		t.Fatalf("got %T, want *PanicError", errs[0])
+	}
+	if panicErr.UpdateId != 1 || panicErr.Attempt != 0 || panicErr.Value != "poison update" {
+		t.Errorf("got %+v", panicErr)
+	}
+	if !strings.Contains(string(panicErr.Stack), "TestPanicSkip") {
+		t.Errorf("stack doesn't point to the handler:\n%s", panicErr.Stack)
+	}
+	if len(offsets) == 0 || offsets[len(offsets)-1] != 2 {
+		t.Errorf("stored offsets %v, want last 2", offsets)
+	}
+}
+
+func TestPanicRetry(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+	conf := srv.Config()
+	conf.PanicPolicy = botan.PanicRetry
+	conf.PanicRetries = 3
+
+	var mu sync.Mutex
+	var errs []error
+	var calls int
+	reported := make(chan struct{})
+	bot, errBot := botan.NewBot(conf, &botan.BotCallbacksContainer{
+		OnMessage: func(bot *botan.Bot, msg *entities.Message) error {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			panic(errors.New("always fails"))
+		},
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if errs = append(errs, err); len(errs) == conf.PanicRetries+1 {
+				close(reported)
+			}
+		},
+	})
+	if errBot != nil {
+		t.Fatal(errBot)
+	}
+	srv.QueueMessage(42, "poison")
+	runUntil(t, bot, reported)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != conf.PanicRetries+1 {
+		t.Errorf("handler called %d times, want %d", calls, conf.PanicRetries+1)
+	}
+	if len(errs) != conf.PanicRetries+1 {
+		t.Fatalf("OnError called %d times, want %d", len(errs), conf.PanicRetries+1)
+	}
+	for attempt, err := range errs {
+		var panicErr *botan.PanicError
+		if !errors.As(err, &panicErr) || panicErr.UpdateId != 1 || panicErr.Attempt != attempt {
+			t.Errorf("attempt %d: got %v", attempt, err)
+		}
+	}
+}
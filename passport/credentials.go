@@ -0,0 +1,34 @@
+package passport
+
+// Credentials is decrypted EncryptedCredentials. It contains secrets required to decrypt every element
+// shared by the user.
+type Credentials struct {
+	SecureData SecureData `json:"secure_data"` // Credentials for encrypted data
+	Nonce      string     `json:"nonce"`       // Bot-specified nonce; make sure it's the same as the one you passed to the request
+}
+
+// Credentials for encrypted Telegram Passport elements, keyed by element type
+// (“personal_details”, “passport”, “address”, “utility_bill” etc.)
+type SecureData map[string]*SecureValue
+
+// Credentials for decrypting data and files of one Telegram Passport element
+type SecureValue struct {
+	Data        *DataCredentials  `json:"data,omitempty"`         // Optional. Credentials for encrypted Telegram Passport data
+	FrontSide   *FileCredentials  `json:"front_side,omitempty"`   // Optional. Credentials for an encrypted document's front side
+	ReverseSide *FileCredentials  `json:"reverse_side,omitempty"` // Optional. Credentials for an encrypted document's reverse side
+	Selfie      *FileCredentials  `json:"selfie,omitempty"`       // Optional. Credentials for an encrypted selfie of the user with a document
+	Translation []FileCredentials `json:"translation,omitempty"`  // Optional. Credentials for an encrypted translation of the document
+	Files       []FileCredentials `json:"files,omitempty"`        // Optional. Credentials for encrypted files
+}
+
+// Credentials for decrypting EncryptedPassportElement.Data
+type DataCredentials struct {
+	DataHash string `json:"data_hash"` // Checksum of encrypted data
+	Secret   string `json:"secret"`    // Secret of encrypted data
+}
+
+// Credentials for decrypting a PassportFile
+type FileCredentials struct {
+	FileHash string `json:"file_hash"` // Checksum of encrypted file
+	Secret   string `json:"secret"`    // Secret of encrypted file
+}
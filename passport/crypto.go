@@ -0,0 +1,56 @@
+package passport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrHashMismatch   = errors.New("passport: hash of decrypted data doesn't match")
+	errInvalidPadding = errors.New("passport: invalid padding of decrypted data")
+)
+
+// Decrypts data with AES-256-CBC. Key and IV are derived from secret and hash of the plain data:
+// SHA512(secret + hash) gives 32 bytes of key and 16 bytes of IV. Decrypted data is verified against hash
+// and padding (its first byte holds the padding length, 32 to 255 bytes) is stripped.
+func decryptData(encrypted []byte, secret []byte, hash []byte) ([]byte, error) {
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("passport: encrypted data length %d is not a multiple of block size", len(encrypted))
+	}
+	secretHash := sha512.Sum512(append(append([]byte{}, secret...), hash...))
+	block, errCipher := aes.NewCipher(secretHash[:32])
+	if errCipher != nil {
+		return nil, errCipher
+	}
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, secretHash[32:48]).CryptBlocks(decrypted, encrypted)
+
+	dataHash := sha256.Sum256(decrypted)
+	if subtle.ConstantTimeCompare(dataHash[:], hash) != 1 {
+		return nil, ErrHashMismatch
+	}
+	padding := int(decrypted[0])
+	if padding < 32 || padding > len(decrypted) {
+		return nil, errInvalidPadding
+	}
+	return decrypted[padding:], nil
+}
+
+// Same as decryptData, but secret and hash are base64-encoded as they come from Telegram
+func decryptDataBase64(encrypted []byte, secret string, hash string) ([]byte, error) {
+	secretBytes, errSecret := base64.StdEncoding.DecodeString(secret)
+	if errSecret != nil {
+		return nil, fmt.Errorf("passport: invalid secret: %w", errSecret)
+	}
+	hashBytes, errHash := base64.StdEncoding.DecodeString(hash)
+	if errHash != nil {
+		return nil, fmt.Errorf("passport: invalid hash: %w", errHash)
+	}
+	return decryptData(encrypted, secretBytes, hashBytes)
+}
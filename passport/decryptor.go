@@ -0,0 +1,216 @@
+// Package passport decrypts Telegram Passport data shared with the bot.
+//
+// Telegram encrypts every element with its own secret. The secrets are sent in EncryptedCredentials, which are
+// encrypted with the bot's public RSA key, so the private key set up with @BotFather is all that's needed:
+//
+//	decryptor := passport.NewDecryptor(privateKey)
+//	data, err := decryptor.Decrypt(update.Message.PassportData)
+//
+// See https://core.telegram.org/passport for details.
+package passport
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+// Decrypted Telegram Passport data. Only elements shared by the user are set.
+type Data struct {
+	Nonce            string              // Bot-specified nonce from the authorization request
+	PersonalDetails  *PersonalDetails    // Optional. Personal details
+	Address          *ResidentialAddress // Optional. Residential address
+	IdDocuments      []*IdDocument       // Optional. Identity documents
+	AddressDocuments []*AddressDocument  // Optional. Documents proving the address
+	PhoneNumber      string              // Optional. Verified phone number
+	Email            string              // Optional. Verified email address
+	Credentials      *Credentials        // Decrypted credentials; use them to decrypt elements on your own
+}
+
+// Decrypts Telegram Passport data with the bot's private key. Safe for concurrent use.
+type Decryptor struct {
+	key *rsa.PrivateKey
+}
+
+func NewDecryptor(key *rsa.PrivateKey) *Decryptor {
+	return &Decryptor{key: key}
+}
+
+// Parses PEM-encoded RSA private key in PKCS #1 ("RSA PRIVATE KEY") or PKCS #8 ("PRIVATE KEY") form
+func ParsePrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("passport: no PEM data found")
+	}
+	if key, errPkcs1 := x509.ParsePKCS1PrivateKey(block.Bytes); errPkcs1 == nil {
+		return key, nil
+	}
+	key, errPkcs8 := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if errPkcs8 != nil {
+		return nil, fmt.Errorf("passport: can't parse private key: %w", errPkcs8)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("passport: private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Decrypts the credentials secret with the bot's private key, then decrypts and verifies credentials
+func (d *Decryptor) DecryptCredentials(ec *entities.EncryptedCredentials) (*Credentials, error) {
+	if ec == nil {
+		return nil, errors.New("passport: no credentials")
+	}
+	encryptedSecret, errSecret := base64.StdEncoding.DecodeString(ec.Secret)
+	if errSecret != nil {
+		return nil, fmt.Errorf("passport: invalid credentials secret: %w", errSecret)
+	}
+	secret, errRsa := rsa.DecryptOAEP(sha1.New(), rand.Reader, d.key, encryptedSecret, nil)
+	if errRsa != nil {
+		return nil, fmt.Errorf("passport: can't decrypt credentials secret: %w", errRsa)
+	}
+	hash, errHash := base64.StdEncoding.DecodeString(ec.Hash)
+	if errHash != nil {
+		return nil, fmt.Errorf("passport: invalid credentials hash: %w", errHash)
+	}
+	encrypted, errData := base64.StdEncoding.DecodeString(ec.Data)
+	if errData != nil {
+		return nil, fmt.Errorf("passport: invalid credentials data: %w", errData)
+	}
+
+	credentialsJson, errDecrypt := decryptData(encrypted, secret, hash)
+	if errDecrypt != nil {
+		return nil, errDecrypt
+	}
+	var credentials Credentials
+	if errUnmarshal := json.Unmarshal(credentialsJson, &credentials); errUnmarshal != nil {
+		return nil, fmt.Errorf("passport: invalid credentials: %w", errUnmarshal)
+	}
+	return &credentials, nil
+}
+
+// Decrypts credentials and all elements of PassportData. Files are not downloaded: they are returned as File
+// values holding credentials required to decrypt them.
+func (d *Decryptor) Decrypt(pd *entities.PassportData) (*Data, error) {
+	if pd == nil {
+		return nil, errors.New("passport: no data")
+	}
+	credentials, errCredentials := d.DecryptCredentials(pd.Credentials)
+	if errCredentials != nil {
+		return nil, errCredentials
+	}
+
+	data := Data{Nonce: credentials.Nonce, Credentials: credentials}
+	for i := range pd.Data {
+		element := &pd.Data[i]
+		if errElement := decryptElement(&data, element, credentials.SecureData[element.Type]); errElement != nil {
+			return nil, fmt.Errorf("passport: element %s: %w", element.Type, errElement)
+		}
+	}
+	return &data, nil
+}
+
+func decryptElement(data *Data, element *entities.EncryptedPassportElement, sv *SecureValue) error {
+	switch element.Type {
+	case "phone_number":
+		data.PhoneNumber = element.PhoneNumber
+		return nil
+	case "email":
+		data.Email = element.Email
+		return nil
+	}
+	if sv == nil {
+		return errors.New("no credentials for element")
+	}
+
+	switch element.Type {
+	case "personal_details":
+		var details PersonalDetails
+		if errData := decryptElementData(element, sv, &details); errData != nil {
+			return errData
+		}
+		data.PersonalDetails = &details
+	case "address":
+		var address ResidentialAddress
+		if errData := decryptElementData(element, sv, &address); errData != nil {
+			return errData
+		}
+		data.Address = &address
+	case "passport", "driver_license", "identity_card", "internal_passport":
+		document := IdDocument{Type: element.Type, Data: &IdDocumentData{}, Hash: element.Hash}
+		if errData := decryptElementData(element, sv, document.Data); errData != nil {
+			return errData
+		}
+		var errFiles error
+		if document.FrontSide, errFiles = newFile(element.FrontSide, sv.FrontSide); errFiles != nil {
+			return errFiles
+		}
+		if document.ReverseSide, errFiles = newFile(element.ReverseSide, sv.ReverseSide); errFiles != nil {
+			return errFiles
+		}
+		if document.Selfie, errFiles = newFile(element.Selfie, sv.Selfie); errFiles != nil {
+			return errFiles
+		}
+		if document.Translation, errFiles = newFiles(element.Translation, sv.Translation); errFiles != nil {
+			return errFiles
+		}
+		data.IdDocuments = append(data.IdDocuments, &document)
+	case "utility_bill", "bank_statement", "rental_agreement", "passport_registration", "temporary_registration":
+		document := AddressDocument{Type: element.Type, Hash: element.Hash}
+		var errFiles error
+		if document.Files, errFiles = newFiles(element.Files, sv.Files); errFiles != nil {
+			return errFiles
+		}
+		if document.Translation, errFiles = newFiles(element.Translation, sv.Translation); errFiles != nil {
+			return errFiles
+		}
+		data.AddressDocuments = append(data.AddressDocuments, &document)
+	}
+	return nil
+}
+
+// Decrypts and verifies element data and unmarshals it into target
+func decryptElementData(element *entities.EncryptedPassportElement, sv *SecureValue, target interface{}) error {
+	if sv.Data == nil {
+		return errors.New("no credentials for element data")
+	}
+	encrypted, errDecode := base64.StdEncoding.DecodeString(element.Data)
+	if errDecode != nil {
+		return fmt.Errorf("invalid data: %w", errDecode)
+	}
+	decrypted, errDecrypt := decryptDataBase64(encrypted, sv.Data.Secret, sv.Data.DataHash)
+	if errDecrypt != nil {
+		return errDecrypt
+	}
+	return json.Unmarshal(decrypted, target)
+}
+
+func newFile(pf *entities.PassportFile, fc *FileCredentials) (*File, error) {
+	if pf == nil {
+		return nil, nil
+	}
+	if fc == nil {
+		return nil, fmt.Errorf("no credentials for file %s", pf.FileId)
+	}
+	return &File{PassportFile: *pf, Credentials: *fc}, nil
+}
+
+// Files and their credentials come in the same order
+func newFiles(pfs []entities.PassportFile, fcs []FileCredentials) ([]*File, error) {
+	if len(pfs) != len(fcs) {
+		return nil, fmt.Errorf("got %d files and %d file credentials", len(pfs), len(fcs))
+	}
+	files := make([]*File, len(pfs))
+	for i := range pfs {
+		files[i] = &File{PassportFile: pfs[i], Credentials: fcs[i]}
+	}
+	return files, nil
+}
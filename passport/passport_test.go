@@ -0,0 +1,186 @@
+package passport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+// Encrypts data the way Telegram does: random padding of paddingLength bytes (its first byte holds the length)
+// is prepended, then data is encrypted with AES-256-CBC using key and IV derived from secret and data hash.
+// Returns encrypted data, secret and hash.
+func encrypt(t *testing.T, data []byte, paddingLength int) ([]byte, []byte, []byte) {
+	t.Helper()
+	padded := make([]byte, paddingLength+len(data))
+	if _, errRand := rand.Read(padded[:paddingLength]); errRand != nil {
+		t.Fatal(errRand)
+	}
+	padded[0] = byte(paddingLength)
+	copy(padded[paddingLength:], data)
+	return encryptPadded(t, padded)
+}
+
+// Encrypts data which already has padding
+func encryptPadded(t *testing.T, padded []byte) ([]byte, []byte, []byte) {
+	t.Helper()
+	secret := make([]byte, 32)
+	if _, errRand := rand.Read(secret); errRand != nil {
+		t.Fatal(errRand)
+	}
+	hash := sha256.Sum256(padded)
+	secretHash := sha512.Sum512(append(append([]byte{}, secret...), hash[:]...))
+	block, errCipher := aes.NewCipher(secretHash[:32])
+	if errCipher != nil {
+		t.Fatal(errCipher)
+	}
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, secretHash[32:48]).CryptBlocks(encrypted, padded)
+	return encrypted, secret, hash[:]
+}
+
+// Padding length for data of n bytes: at least 32 bytes, total length is a multiple of AES block size
+func paddingFor(n int) int {
+	return 32 + (aes.BlockSize-(32+n)%aes.BlockSize)%aes.BlockSize
+}
+
+// Encrypts JSON-serialized value; returns base64-encoded data and its credentials
+func encryptValue(t *testing.T, value interface{}) (string, *DataCredentials) {
+	t.Helper()
+	plain, errMarshal := json.Marshal(value)
+	if errMarshal != nil {
+		t.Fatal(errMarshal)
+	}
+	encrypted, secret, hash := encrypt(t, plain, paddingFor(len(plain)))
+	return base64.StdEncoding.EncodeToString(encrypted), &DataCredentials{
+		DataHash: base64.StdEncoding.EncodeToString(hash),
+		Secret:   base64.StdEncoding.EncodeToString(secret),
+	}
+}
+
+// Encrypts credentials with the public key of the bot
+func encryptCredentials(t *testing.T, key *rsa.PublicKey, credentials *Credentials) *entities.EncryptedCredentials {
+	t.Helper()
+	plain, errMarshal := json.Marshal(credentials)
+	if errMarshal != nil {
+		t.Fatal(errMarshal)
+	}
+	encrypted, secret, hash := encrypt(t, plain, paddingFor(len(plain)))
+	encryptedSecret, errRsa := rsa.EncryptOAEP(sha1.New(), rand.Reader, key, secret, nil)
+	if errRsa != nil {
+		t.Fatal(errRsa)
+	}
+	return &entities.EncryptedCredentials{
+		Data:   base64.StdEncoding.EncodeToString(encrypted),
+		Hash:   base64.StdEncoding.EncodeToString(hash),
+		Secret: base64.StdEncoding.EncodeToString(encryptedSecret),
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	key, errKey := rsa.GenerateKey(rand.Reader, 2048)
+	if errKey != nil {
+		t.Fatal(errKey)
+	}
+
+	details := PersonalDetails{FirstName: "John", LastName: "Doe", BirthDate: "01.01.1990", Gender: "male", CountryCode: "GB"}
+	document := IdDocumentData{DocumentNo: "AB123456", ExpiryDate: "01.01.2030"}
+	address := ResidentialAddress{StreetLine1: "221B Baker Street", City: "London", CountryCode: "GB", PostCode: "NW1 6XE"}
+	detailsData, detailsCredentials := encryptValue(t, details)
+	documentData, documentCredentials := encryptValue(t, document)
+	addressData, addressCredentials := encryptValue(t, address)
+
+	frontSide, frontSecret, frontHash := encrypt(t, []byte("jpeg"), paddingFor(4))
+	frontCredentials := &FileCredentials{
+		FileHash: base64.StdEncoding.EncodeToString(frontHash),
+		Secret:   base64.StdEncoding.EncodeToString(frontSecret),
+	}
+
+	pd := &entities.PassportData{
+		Data: []entities.EncryptedPassportElement{
+			{Type: "personal_details", Data: detailsData, Hash: "h1"},
+			{Type: "passport", Data: documentData, FrontSide: &entities.PassportFile{FileId: "front"}, Hash: "h2"},
+			{Type: "address", Data: addressData, Hash: "h3"},
+			{Type: "email", Email: "john@example.com", Hash: "h4"},
+		},
+		Credentials: encryptCredentials(t, &key.PublicKey, &Credentials{
+			SecureData: SecureData{
+				"personal_details": {Data: detailsCredentials},
+				"passport":         {Data: documentCredentials, FrontSide: frontCredentials},
+				"address":          {Data: addressCredentials},
+			},
+			Nonce: "nonce",
+		}),
+	}
+
+	data, errDecrypt := NewDecryptor(key).Decrypt(pd)
+	if errDecrypt != nil {
+		t.Fatal(errDecrypt)
+	}
+	if data.Nonce != "nonce" || data.Email != "john@example.com" {
+		t.Errorf("nonce %q, email %q", data.Nonce, data.Email)
+	}
+	if data.PersonalDetails == nil || *data.PersonalDetails != details {
+		t.Errorf("personal details %+v", data.PersonalDetails)
+	}
+	if data.Address == nil || *data.Address != address {
+		t.Errorf("address %+v", data.Address)
+	}
+	if len(data.IdDocuments) != 1 {
+		t.Fatalf("got %d identity documents", len(data.IdDocuments))
+	}
+	passport := data.IdDocuments[0]
+	if passport.Type != "passport" || *passport.Data != document || passport.Hash != "h2" || passport.FrontSide == nil {
+		t.Fatalf("passport %+v", passport)
+	}
+	if front, errFile := passport.FrontSide.Decrypt(frontSide); errFile != nil || string(front) != "jpeg" {
+		t.Errorf("front side %q, %v", front, errFile)
+	}
+
+	// tampered element hash
+	detailsCredentials.DataHash = addressCredentials.DataHash
+	pd.Credentials = encryptCredentials(t, &key.PublicKey, &Credentials{
+		SecureData: SecureData{"personal_details": {Data: detailsCredentials}},
+	})
+	pd.Data = pd.Data[:1]
+	if _, errTampered := NewDecryptor(key).Decrypt(pd); !errors.Is(errTampered, ErrHashMismatch) {
+		t.Errorf("got %v, want ErrHashMismatch", errTampered)
+	}
+}
+
+func TestDecryptDataPadding(t *testing.T) {
+	data := []byte("0123456789abcdef") // one block
+	for _, tt := range []struct {
+		paddingLength int
+		firstByte     byte // stored padding length
+		wantErr       error
+	}{
+		{paddingLength: 32, firstByte: 32},
+		{paddingLength: 240, firstByte: 240},
+		{paddingLength: 16, firstByte: 16, wantErr: errInvalidPadding},  // shorter than 32 bytes
+		{paddingLength: 32, firstByte: 31, wantErr: errInvalidPadding},  // claims less than 32 bytes
+		{paddingLength: 32, firstByte: 255, wantErr: errInvalidPadding}, // longer than the data
+	} {
+		padded := make([]byte, tt.paddingLength+len(data))
+		padded[0] = tt.firstByte
+		copy(padded[tt.paddingLength:], data)
+		encrypted, secret, hash := encryptPadded(t, padded)
+		decrypted, errDecrypt := decryptData(encrypted, secret, hash)
+		if !errors.Is(errDecrypt, tt.wantErr) {
+			t.Errorf("padding %d (%d): got %v, want %v", tt.paddingLength, tt.firstByte, errDecrypt, tt.wantErr)
+			continue
+		}
+		if tt.wantErr == nil && string(decrypted) != string(data) {
+			t.Errorf("padding %d: decrypted %q", tt.paddingLength, decrypted)
+		}
+	}
+}
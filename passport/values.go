@@ -0,0 +1,81 @@
+package passport
+
+import (
+	"bytes"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/entities"
+)
+
+// Personal details of the user, decrypted from “personal_details” element
+type PersonalDetails struct {
+	FirstName            string `json:"first_name"`                   // First Name
+	LastName             string `json:"last_name"`                    // Last Name
+	MiddleName           string `json:"middle_name,omitempty"`        // Optional. Middle Name
+	BirthDate            string `json:"birth_date"`                   // Date of birth in DD.MM.YYYY format
+	Gender               string `json:"gender"`                       // Gender, male or female
+	CountryCode          string `json:"country_code"`                 // Citizenship (ISO 3166-1 alpha-2 country code)
+	ResidenceCountryCode string `json:"residence_country_code"`       // Country of residence (ISO 3166-1 alpha-2 country code)
+	FirstNameNative      string `json:"first_name_native,omitempty"`  // First Name in the language of the user's country of residence
+	LastNameNative       string `json:"last_name_native,omitempty"`   // Last Name in the language of the user's country of residence
+	MiddleNameNative     string `json:"middle_name_native,omitempty"` // Optional. Middle Name in the language of the user's country of residence
+}
+
+// Residential address of the user, decrypted from “address” element
+type ResidentialAddress struct {
+	StreetLine1 string `json:"street_line1"`           // First line for the address
+	StreetLine2 string `json:"street_line2,omitempty"` // Optional. Second line for the address
+	City        string `json:"city"`                   // City
+	State       string `json:"state,omitempty"`        // Optional. State
+	CountryCode string `json:"country_code"`           // ISO 3166-1 alpha-2 country code
+	PostCode    string `json:"post_code"`              // Address post code
+}
+
+// Data of an identity document, decrypted from “passport”, “driver_license”, “identity_card” or
+// “internal_passport” element
+type IdDocumentData struct {
+	DocumentNo string `json:"document_no"`           // Document number
+	ExpiryDate string `json:"expiry_date,omitempty"` // Optional. Date of expiry, in DD.MM.YYYY format
+}
+
+// Identity document: passport, driver license, identity card or internal passport
+type IdDocument struct {
+	Type        string          // Element type
+	Data        *IdDocumentData // Decrypted document data
+	FrontSide   *File           // Optional. Front side of the document
+	ReverseSide *File           // Optional. Reverse side of the document
+	Selfie      *File           // Optional. Selfie of the user holding the document
+	Translation []*File         // Optional. Translated versions of the document
+	Hash        string          // Element hash for PassportElementErrorUnspecified
+}
+
+// Document proving the address: utility bill, bank statement, rental agreement, passport registration
+// or temporary registration
+type AddressDocument struct {
+	Type        string  // Element type
+	Files       []*File // Scans of the document
+	Translation []*File // Optional. Translated versions of the document
+	Hash        string  // Element hash for PassportElementErrorUnspecified
+}
+
+// Encrypted PassportFile together with credentials required to decrypt it. Files are not sent with the update,
+// so they have to be downloaded first: use Download or get contents with Bot.DownloadFile and pass it to Decrypt.
+type File struct {
+	entities.PassportFile
+	Credentials FileCredentials
+}
+
+// Decrypts and verifies contents of the file
+func (f *File) Decrypt(encrypted []byte) ([]byte, error) {
+	return decryptDataBase64(encrypted, f.Credentials.Secret, f.Credentials.FileHash)
+}
+
+// Downloads the file and returns its decrypted contents. Passport files are JPEG images of up to 10MB,
+// so they are kept in memory.
+func (f *File) Download(bot *botan.Bot) ([]byte, error) {
+	var encrypted bytes.Buffer
+	if errDownload := bot.DownloadFile(f.FileId, &encrypted); errDownload != nil {
+		return nil, errDownload
+	}
+	return f.Decrypt(encrypted.Bytes())
+}
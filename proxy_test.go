@@ -0,0 +1,290 @@
+package botan
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// Bot API stub served over TLS, so that HTTP proxies have to tunnel requests with CONNECT
+func newTLSApiServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"test_bot"}}`)
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func newProxiedBot(t *testing.T, api *httptest.Server, proxyUrl string) *Bot {
+	t.Helper()
+	bot, errBot := NewBot(&Config{Token: "1:test", APIBaseURL: api.URL, ProxyURL: proxyUrl}, &BotCallbacksContainer{})
+	if errBot != nil {
+		t.Fatal(errBot)
+	}
+	// clients share the transport; trust the certificate of the stub
+	bot.requestGate.postClient.Transport.(*http.Transport).TLSClientConfig = api.Client().Transport.(*http.Transport).TLSClientConfig
+	return bot
+}
+
+// Minimal SOCKS5 proxy (RFC 1928) supporting CONNECT, with optional username/password authentication (RFC 1929)
+type socks5Proxy struct {
+	listener    net.Listener
+	username    string
+	password    string
+	connections atomic.Int32 // tunnels established
+}
+
+func newSocks5Proxy(t *testing.T, username string, password string) *socks5Proxy {
+	t.Helper()
+	listener, errListen := net.Listen("tcp", "127.0.0.1:0")
+	if errListen != nil {
+		t.Fatal(errListen)
+	}
+	proxy := &socks5Proxy{listener: listener, username: username, password: password}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, errAccept := listener.Accept()
+			if errAccept != nil {
+				return
+			}
+			go proxy.serve(conn)
+		}
+	}()
+	return proxy
+}
+
+func (p *socks5Proxy) addr() string {
+	return p.listener.Addr().String()
+}
+
+func (p *socks5Proxy) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	// greeting: version, methods
+	header := make([]byte, 2)
+	if _, errRead := io.ReadFull(r, header); errRead != nil || header[0] != 5 {
+		return
+	}
+	methods := make([]byte, header[1])
+	if _, errRead := io.ReadFull(r, methods); errRead != nil {
+		return
+	}
+	method := byte(0x00) // no authentication
+	if p.username != "" {
+		method = 0x02 // username/password
+	}
+	if !strings.Contains(string(methods), string([]byte{method})) {
+		conn.Write([]byte{5, 0xff})
+		return
+	}
+	conn.Write([]byte{5, method})
+	if method == 0x02 {
+		username, password, errAuth := readSocks5Credentials(r)
+		if errAuth != nil || username != p.username || password != p.password {
+			conn.Write([]byte{1, 1})
+			return
+		}
+		conn.Write([]byte{1, 0})
+	}
+
+	// request: version, command, reserved, address type, address, port
+	request := make([]byte, 4)
+	if _, errRead := io.ReadFull(r, request); errRead != nil || request[1] != 1 {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1, 4: // IPv4, IPv6
+		ip := make([]byte, map[byte]int{1: 4, 4: 16}[request[3]])
+		if _, errRead := io.ReadFull(r, ip); errRead != nil {
+			return
+		}
+		host = net.IP(ip).String()
+	case 3: // domain name
+		length, errRead := r.ReadByte()
+		if errRead != nil {
+			return
+		}
+		name := make([]byte, length)
+		if _, errRead = io.ReadFull(r, name); errRead != nil {
+			return
+		}
+		host = string(name)
+	default:
+		return
+	}
+	port := make([]byte, 2)
+	if _, errRead := io.ReadFull(r, port); errRead != nil {
+		return
+	}
+
+	target, errDial := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+	if errDial != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	p.connections.Add(1)
+	tunnel(conn, r, target)
+}
+
+func readSocks5Credentials(r *bufio.Reader) (string, string, error) {
+	version, errVersion := r.ReadByte()
+	if errVersion != nil || version != 1 {
+		return "", "", errors.New("unsupported authentication version")
+	}
+	readString := func() (string, error) {
+		length, errLength := r.ReadByte()
+		if errLength != nil {
+			return "", errLength
+		}
+		value := make([]byte, length)
+		_, errRead := io.ReadFull(r, value)
+		return string(value), errRead
+	}
+	username, errUsername := readString()
+	if errUsername != nil {
+		return "", "", errUsername
+	}
+	password, errPassword := readString()
+	return username, password, errPassword
+}
+
+// Copies data between client and target until either side closes the connection
+func tunnel(client net.Conn, clientReader io.Reader, target net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(target, clientReader)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(client, target)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// HTTP proxy supporting CONNECT tunnels only
+func newConnectProxy(t *testing.T, connections *atomic.Int32) *httptest.Server {
+	t.Helper()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		target, errDial := net.Dial("tcp", r.Host)
+		if errDial != nil {
+			http.Error(w, errDial.Error(), http.StatusBadGateway)
+			return
+		}
+		defer target.Close()
+		client, buffered, errHijack := w.(http.Hijacker).Hijack()
+		if errHijack != nil {
+			return
+		}
+		defer client.Close()
+		client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		connections.Add(1)
+		tunnel(client, buffered, target)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+func TestSocks5Proxy(t *testing.T) {
+	api := newTLSApiServer(t)
+	tests := []struct {
+		name     string
+		username string
+		password string
+		proxyUrl func(addr string) string
+	}{
+		{"socks5 without auth", "", "", func(addr string) string { return "socks5://" + addr }},
+		{"socks5h without auth", "", "", func(addr string) string { return "socks5h://" + addr }},
+		{"socks5 with auth", "user", "p@ss:word", func(addr string) string { return "socks5://user:p%40ss%3Aword@" + addr }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := newSocks5Proxy(t, tt.username, tt.password)
+			bot := newProxiedBot(t, api, tt.proxyUrl(proxy.addr()))
+			me, errMe := bot.GetMe()
+			if errMe != nil {
+				t.Fatal(errMe)
+			}
+			if me.Username != "test_bot" {
+				t.Errorf("got %+v", me)
+			}
+			if proxy.connections.Load() == 0 {
+				t.Error("request didn't go through the proxy")
+			}
+		})
+	}
+}
+
+func TestSocks5ProxyWrongPassword(t *testing.T) {
+	api := newTLSApiServer(t)
+	proxy := newSocks5Proxy(t, "user", "secret")
+	bot := newProxiedBot(t, api, "socks5://user:wrong@"+proxy.addr())
+	if _, errMe := bot.GetMe(); errMe == nil {
+		t.Fatal("request with wrong proxy password succeeded")
+	}
+	if proxy.connections.Load() != 0 {
+		t.Error("tunnel established with wrong password")
+	}
+}
+
+func TestHttpConnectProxy(t *testing.T) {
+	api := newTLSApiServer(t)
+	var connections atomic.Int32
+	proxy := newConnectProxy(t, &connections)
+	bot := newProxiedBot(t, api, proxy.URL)
+	if _, errMe := bot.GetMe(); errMe != nil {
+		t.Fatal(errMe)
+	}
+	if connections.Load() == 0 {
+		t.Error("request didn't go through the proxy")
+	}
+}
+
+func TestNewBotRejectsInvalidProxy(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Config
+	}{
+		{"unsupported scheme", Config{ProxyURL: "ftp://proxy:21"}},
+		{"no host", Config{ProxyURL: "socks5://"}},
+		{"not a URL", Config{ProxyURL: "://proxy"}},
+		{"socks5 without port", Config{ProxyURL: "socks5://proxy"}},
+		{"socks5 without password", Config{ProxyURL: "socks5://user@proxy:1080"}},
+		{"socks5 username too long", Config{ProxyURL: "socks5://" + strings.Repeat("u", 256) + ":secret@proxy:1080"}},
+		{"deprecated field", Config{Socks5ConnectionString: "socks5://proxy"}},
+		{"url and environment", Config{ProxyURL: "socks5://proxy:1080", ProxyFromEnvironment: true}},
+		{"url and http client", Config{ProxyURL: "socks5://proxy:1080", HTTPClient: &http.Client{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := tt.conf
+			conf.Token = "1:test"
+			_, errBot := NewBot(&conf, &BotCallbacksContainer{})
+			if errBot == nil {
+				t.Fatal("invalid proxy settings accepted")
+			}
+			if strings.Contains(errBot.Error(), "secret") {
+				t.Errorf("error reveals proxy password: %v", errBot)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package botan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+// ErrRateLimited is returned by Bot methods when RateLimitFail policy is used and a message can't be sent
+// right away without exceeding outgoing limits.
+var ErrRateLimited = errors.New("outgoing rate limit reached")
+
+// Behaviour of the outgoing limiter when a limit is reached
+type RateLimitPolicy int
+
+const (
+	RateLimitOff  RateLimitPolicy = iota // don't limit outgoing requests
+	RateLimitWait                        // block until the message may be sent or request context is done
+	RateLimitFail                        // return ErrRateLimited immediately
+)
+
+// Number of requests allowed per time period
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Default limits according to Telegram Bot FAQ
+var (
+	defaultGlobalRateLimit      = RateLimit{Requests: 30, Per: time.Second}
+	defaultPrivateChatRateLimit = RateLimit{Requests: 1, Per: time.Second}
+	defaultGroupChatRateLimit   = RateLimit{Requests: 20, Per: time.Minute}
+)
+
+// RateLimiter stores token buckets. Implement it on top of a shared storage (e.g. Redis) to make several
+// bot processes share the same limits; NewMemoryRateLimiter is used by default.
+type RateLimiter interface {
+	// Reserve takes a token from the bucket identified by key, which holds limit.Requests tokens and is refilled
+	// at limit.Requests per limit.Per. Returns delay after which the request may be sent. If the delay exceeds
+	// maxWait, no token is taken and false is returned.
+	Reserve(ctx context.Context, key string, limit RateLimit, maxWait time.Duration) (time.Duration, bool, error)
+	// Release returns a token taken by Reserve when the request isn't sent after all.
+	Release(ctx context.Context, key string, limit RateLimit) error
+}
+
+// Limits outgoing messages of a bot globally and per chat.
+type outgoingLimiter struct {
+	botKey      string // prefix of bucket keys: limits are per bot, while a limiter may be shared by several bots
+	policy      RateLimitPolicy
+	limiter     RateLimiter
+	global      RateLimit
+	privateChat RateLimit
+	groupChat   RateLimit
+}
+
+// Blocks until the request may be sent according to policy. Only requests sending messages to a chat are limited.
+func (ol *outgoingLimiter) wait(ctx context.Context, url string, payload interface{}) error {
+	if ol.policy == RateLimitOff || !isSendMethod(methodFromUrl(url)) {
+		return nil
+	}
+	chatKey, isPrivate, ok := chatIdFromPayload(payload)
+	if !ok {
+		return nil
+	}
+
+	var maxWait time.Duration // zero for RateLimitFail
+	if ol.policy == RateLimitWait {
+		maxWait = time.Duration(1<<63 - 1)
+		if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+			maxWait = time.Until(deadline)
+		}
+	}
+
+	// global bucket is reserved first: when it's exhausted, as it usually is during broadcasts, no chat token is spent
+	globalKey := ol.botKey + ":global"
+	globalDelay, globalOk, errGlobal := ol.limiter.Reserve(ctx, globalKey, ol.global, maxWait)
+	if errGlobal != nil {
+		return errGlobal
+	}
+	if !globalOk {
+		return fmt.Errorf("%w: global, retry in %s", ErrRateLimited, globalDelay)
+	}
+	chatLimit := ol.groupChat
+	if isPrivate {
+		chatLimit = ol.privateChat
+	}
+	chatDelay, chatOk, errChat := ol.limiter.Reserve(ctx, ol.botKey+":chat:"+chatKey, chatLimit, maxWait)
+	if errChat != nil || !chatOk {
+		// message isn't sent, so it mustn't take a place of other chats in the global bucket;
+		// if the token can't be returned, it's just lost until the bucket is refilled
+		_ = ol.limiter.Release(ctx, globalKey, ol.global)
+		if errChat != nil {
+			return errChat
+		}
+		return fmt.Errorf("%w: chat %s, retry in %s", ErrRateLimited, chatKey, chatDelay)
+	}
+
+	delay := chatDelay
+	if globalDelay > delay {
+		delay = globalDelay
+	}
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// Bucket key prefix for the bot: its ID, which is the part of the token before the colon
+func rateLimitBotKey(token string) string {
+	if i := strings.IndexByte(token, ':'); i > 0 {
+		token = token[:i]
+	}
+	return "bot" + token
+}
+
+// methods which send messages to chats and thus are subject to Telegram limits;
+// sendChatAction and edits don't produce new messages
+var sendMethods = map[string]bool{
+	MethodSendMessage:    true,
+	MethodForwardMessage: true,
+	MethodSendPhoto:      true,
+	MethodSendAudio:      true,
+	MethodSendDocument:   true,
+	MethodSendVideo:      true,
+	MethodSendAnimation:  true,
+	MethodSendVoice:      true,
+	MethodSendVideoNote:  true,
+	MethodSendMediaGroup: true,
+	MethodSendLocation:   true,
+	MethodSendVenue:      true,
+	MethodSendContact:    true,
+	MethodSendPoll:       true,
+	MethodSendSticker:    true,
+	MethodSendInvoice:    true,
+	MethodSendGame:       true,
+}
+
+func isSendMethod(method string) bool {
+	return sendMethods[method]
+}
+
+// Extracts ChatId field from request struct. Returns key of the chat and true as second value for private chats:
+// positive IDs belong to users, groups and channels have negative IDs or are addressed by @username.
+func chatIdFromPayload(payload interface{}) (string, bool, bool) {
+	v := reflect.ValueOf(payload)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", false, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", false, false
+	}
+	field := v.FieldByName("ChatId")
+	if !field.IsValid() {
+		return "", false, false
+	}
+
+	chatId := field.Interface()
+	switch t := chatId.(type) {
+	case entities.ChatId:
+		chatId = t.ExactType
+	case *entities.ChatId:
+		if t == nil {
+			return "", false, false
+		}
+		chatId = t.ExactType
+	}
+	switch t := chatId.(type) {
+	case int:
+		return strconv.Itoa(t), t > 0, true
+	case int64:
+		return strconv.FormatInt(t, 10), t > 0, true
+	case string:
+		if t == "" {
+			return "", false, false
+		}
+		if id, errConv := strconv.ParseInt(t, 10, 64); errConv == nil {
+			return t, id > 0, true
+		}
+		return t, false, true
+	}
+	return "", false, false
+}
+
+// In-memory RateLimiter; safe for concurrent use by several bots in one process. The zero value is ready to use.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens   float64 // may be negative when requests are waiting for tokens
+	updated  time.Time
+	capacity float64
+	perToken time.Duration
+}
+
+// refills bucket according to time passed since the last update
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += float64(now.Sub(b.updated)) / float64(b.perToken)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.updated = now
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: make(map[string]*tokenBucket), lastSweep: time.Now()}
+}
+
+func (ml *MemoryRateLimiter) Reserve(_ context.Context, key string, limit RateLimit, maxWait time.Duration) (time.Duration, bool, error) {
+	if limit.Requests < 1 || limit.Per <= 0 {
+		return 0, true, nil // no limit
+	}
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	now := time.Now()
+	if ml.buckets == nil {
+		ml.buckets = make(map[string]*tokenBucket)
+		ml.lastSweep = now
+	}
+	ml.sweep(now)
+
+	b, ok := ml.buckets[key]
+	if !ok {
+		b = &tokenBucket{
+			tokens:   float64(limit.Requests),
+			updated:  now,
+			capacity: float64(limit.Requests),
+			perToken: limit.Per / time.Duration(limit.Requests),
+		}
+		ml.buckets[key] = b
+	}
+	b.refill(now)
+
+	var delay time.Duration
+	if b.tokens < 1 {
+		delay = time.Duration((1 - b.tokens) * float64(b.perToken))
+	}
+	if delay > maxWait {
+		return delay, false, nil
+	}
+	b.tokens--
+	return delay, true, nil
+}
+
+func (ml *MemoryRateLimiter) Release(_ context.Context, key string, limit RateLimit) error {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	if b, ok := ml.buckets[key]; ok {
+		b.refill(time.Now())
+		if b.tokens++; b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	return nil
+}
+
+// drops buckets which are full again, so that map doesn't grow with every chat bot has ever written to
+func (ml *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(ml.lastSweep) < time.Minute {
+		return
+	}
+	ml.lastSweep = now
+	for key, b := range ml.buckets {
+		if b.refill(now); b.tokens >= b.capacity {
+			delete(ml.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package botan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testLimiter(token string, limiter RateLimiter, global RateLimit) *outgoingLimiter {
+	return &outgoingLimiter{
+		botKey:      rateLimitBotKey(token),
+		policy:      RateLimitFail,
+		limiter:     limiter,
+		global:      global,
+		privateChat: RateLimit{Requests: 1, Per: time.Hour},
+		groupChat:   RateLimit{Requests: 1, Per: time.Hour},
+	}
+}
+
+func sendTo(ol *outgoingLimiter, chatId int) error {
+	return ol.wait(context.Background(), "https://api.telegram.org/bot1:t/"+MethodSendMessage, &SendMessageRequest{ChatId: chatId})
+}
+
+func TestMemoryRateLimiterZeroValue(t *testing.T) {
+	var ml MemoryRateLimiter
+	limit := RateLimit{Requests: 1, Per: time.Hour}
+	if _, ok, err := ml.Reserve(context.Background(), "key", limit, 0); err != nil || !ok {
+		t.Fatalf("first reservation: ok %v, err %v", ok, err)
+	}
+	if _, ok, _ := ml.Reserve(context.Background(), "key", limit, 0); ok {
+		t.Fatal("second reservation must exceed the limit")
+	}
+}
+
+func TestRateLimitsArePerBot(t *testing.T) {
+	shared := NewMemoryRateLimiter()
+	global := RateLimit{Requests: 1, Per: time.Hour}
+	first := testLimiter("111:first", shared, global)
+	second := testLimiter("222:second", shared, global)
+
+	if err := sendTo(first, 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := sendTo(second, 42); err != nil {
+		t.Fatalf("bots sharing a limiter throttle each other: %v", err)
+	}
+	if err := sendTo(first, 43); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+}
+
+func TestFailedGlobalReservationKeepsChatToken(t *testing.T) {
+	shared := NewMemoryRateLimiter()
+	ol := testLimiter("111:first", shared, RateLimit{Requests: 1, Per: time.Hour})
+	if err := sendTo(ol, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := sendTo(ol, 2); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+
+	if _, ok, _ := shared.Reserve(context.Background(), "bot111:chat:2", ol.privateChat, 0); !ok {
+		t.Fatal("chat token was spent by the request which wasn't sent")
+	}
+}
+
+func TestFailedChatReservationKeepsGlobalToken(t *testing.T) {
+	shared := NewMemoryRateLimiter()
+	ol := testLimiter("111:first", shared, RateLimit{Requests: 2, Per: time.Hour})
+	if err := sendTo(ol, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := sendTo(ol, 1); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+
+	if err := sendTo(ol, 2); err != nil {
+		t.Fatalf("global token was spent by the request which wasn't sent: %v", err)
+	}
+}
+
+func TestOnlyMessagesAreLimited(t *testing.T) {
+	ol := testLimiter("111:first", NewMemoryRateLimiter(), RateLimit{Requests: 1, Per: time.Hour})
+	if err := sendTo(ol, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{MethodSendChatAction, MethodEditMessageText, MethodDeleteMessage} {
+		if err := ol.wait(context.Background(), "https://api.telegram.org/bot1:t/"+method, &SendChatActionRequest{ChatId: 1}); err != nil {
+			t.Errorf("%s: %v", method, err)
+		}
+	}
+	if err := ol.wait(context.Background(), "https://api.telegram.org/bot1:t/"+MethodSendPhoto, &SendPhotoRequest{ChatId: 2}); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("sendPhoto: got %v, want ErrRateLimited", err)
+	}
+}
@@ -0,0 +1,61 @@
+package botan
+
+import (
+	"errors"
+	"math/rand"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelayMilliseconds = 500
+	defaultRetryMaxDelaySeconds       = 60
+)
+
+// Decides whether a failed request should be repeated and how long to wait before that.
+// Flood control errors are retried after retry_after seconds returned by Telegram, server errors (5xx) and
+// network errors are retried with exponential backoff and full jitter. Zero value never retries.
+type retryPolicy struct {
+	maxRetries int           // number of repeats after the first attempt
+	baseDelay  time.Duration // backoff delay for the first repeat; doubles with each following one
+	maxDelay   time.Duration // upper bound for a single delay; requests asking to wait longer are not retried
+}
+
+// Returns delay before the next attempt and true if request which failed with err on the given attempt
+// (zero-based) should be repeated.
+func (rp *retryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	if attempt >= rp.maxRetries {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.IsFloodWait() && apiErr.RetryAfter > 0:
+			delay := time.Duration(apiErr.RetryAfter) * time.Second
+			return delay, delay <= rp.maxDelay
+		case apiErr.IsFloodWait(), apiErr.ErrorCode >= 500:
+			return rp.exponentialDelay(attempt), true
+		}
+		return 0, false // request itself is wrong, repeating won't help
+	}
+
+	// failed to connect or to read the response
+	var netErr *url.Error
+	if errors.As(err, &netErr) {
+		return rp.exponentialDelay(attempt), true
+	}
+	return 0, false
+}
+
+// random delay in [0, baseDelay * 2^attempt), capped with maxDelay
+func (rp *retryPolicy) exponentialDelay(attempt int) time.Duration {
+	ceiling := rp.baseDelay << uint(attempt)
+	if ceiling <= 0 || ceiling > rp.maxDelay { // <= 0 on overflow
+		ceiling = rp.maxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
@@ -0,0 +1,167 @@
+package botan_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+	"github.com/isvinogradov/botan/entities"
+)
+
+func TestRetries(t *testing.T) {
+	sendMessage := func(bot *botan.Bot) error {
+		_, err := bot.SendMessage(&botan.SendMessageRequest{ChatId: 42, Text: "hi"})
+		return err
+	}
+	tests := []struct {
+		name         string
+		maxRetries   int
+		baseDelayMs  int
+		maxDelaySec  int
+		responses    []botantest.Response
+		method       string // method called by send; sendMessage if empty
+		send         func(bot *botan.Bot) error
+		cancelAfter  time.Duration // cancels the context of the bot if non-zero
+		wantCalls    int
+		wantErrCode  int           // 0 if the request must succeed in the end
+		minGap       time.Duration // bounds for delays between attempts
+		maxGap       time.Duration
+		wantJitter   bool // delays between attempts must differ
+		wantCanceled bool
+	}{
+		{
+			name:       "flood wait waits retry_after",
+			maxRetries: 3,
+			responses:  []botantest.Response{botantest.FloodWait(1), botantest.OK(nil)},
+			send:       sendMessage,
+			wantCalls:  2,
+			minGap:     time.Second,
+			maxGap:     2 * time.Second,
+		},
+		{
+			name:        "retry_after above max delay gives up",
+			maxRetries:  3,
+			maxDelaySec: 1,
+			responses:   []botantest.Response{botantest.FloodWait(2), botantest.OK(nil)},
+			send:        sendMessage,
+			wantCalls:   1,
+			wantErrCode: 429,
+		},
+		{
+			name:        "server errors use capped backoff",
+			maxRetries:  3,
+			baseDelayMs: 10000, // every delay is capped with max delay
+			maxDelaySec: 1,
+			responses: []botantest.Response{
+				botantest.Error(502, "Bad Gateway"),
+				botantest.Error(500, "Internal Server Error"),
+				botantest.Error(503, "Service Unavailable"),
+				botantest.OK(nil),
+			},
+			send:       sendMessage,
+			wantCalls:  4,
+			maxGap:     time.Second + 200*time.Millisecond,
+			wantJitter: true,
+		},
+		{
+			name:        "retries stop at MaxRetries",
+			maxRetries:  2,
+			baseDelayMs: 1,
+			responses: []botantest.Response{
+				botantest.Error(500, "Internal Server Error"),
+				botantest.Error(500, "Internal Server Error"),
+				botantest.Error(500, "Internal Server Error"),
+				botantest.OK(nil),
+			},
+			send:        sendMessage,
+			wantCalls:   3,
+			wantErrCode: 500,
+		},
+		{
+			name:         "cancelled context stops waiting",
+			maxRetries:   3,
+			responses:    []botantest.Response{botantest.FloodWait(30), botantest.OK(nil)},
+			send:         sendMessage,
+			cancelAfter:  100 * time.Millisecond,
+			wantCalls:    1,
+			wantErrCode:  429,
+			wantCanceled: true,
+		},
+		{
+			name:        "reader uploads are not repeated",
+			maxRetries:  3,
+			baseDelayMs: 1,
+			responses:   []botantest.Response{botantest.Error(500, "Internal Server Error"), botantest.OK(nil)},
+			method:      botan.MethodSendPhoto,
+			send: func(bot *botan.Bot) error {
+				_, err := bot.SendPhoto(&botan.SendPhotoRequest{
+					ChatId: 42,
+					Photo:  entities.InputFileFromReader("photo.jpg", strings.NewReader("jpeg")),
+				})
+				return err
+			},
+			wantCalls:   1,
+			wantErrCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := botantest.NewServer()
+			defer srv.Close()
+			conf := srv.Config()
+			conf.MaxRetries = tt.maxRetries
+			conf.RetryBaseDelayMilliseconds = tt.baseDelayMs
+			conf.RetryMaxDelaySeconds = tt.maxDelaySec
+			bot, errBot := botan.NewBot(conf, &botan.BotCallbacksContainer{})
+			if errBot != nil {
+				t.Fatal(errBot)
+			}
+			if tt.cancelAfter > 0 {
+				ctx, cancel := context.WithTimeout(context.Background(), tt.cancelAfter)
+				defer cancel()
+				bot = bot.WithContext(ctx)
+			}
+			method := tt.method
+			if method == "" {
+				method = botan.MethodSendMessage
+			}
+			srv.Respond(method, tt.responses...)
+
+			started := time.Now()
+			errSend := tt.send(bot)
+			elapsed := time.Since(started)
+
+			var apiErr *botan.APIError
+			switch {
+			case tt.wantErrCode == 0 && errSend != nil:
+				t.Errorf("got %v, want success", errSend)
+			case tt.wantErrCode != 0 && (!errors.As(errSend, &apiErr) || apiErr.ErrorCode != tt.wantErrCode):
+				t.Errorf("got %v, want API error %d", errSend, tt.wantErrCode)
+			}
+			if tt.wantCanceled && elapsed > time.Second {
+				t.Errorf("returned after %v, want right after cancellation", elapsed)
+			}
+
+			calls := srv.Calls(method)
+			if len(calls) != tt.wantCalls {
+				t.Fatalf("%d calls, want %d", len(calls), tt.wantCalls)
+			}
+			gaps := make(map[time.Duration]bool)
+			for i := 1; i < len(calls); i++ {
+				gap := calls[i].Time.Sub(calls[i-1].Time)
+				if gap < tt.minGap || (tt.maxGap > 0 && gap > tt.maxGap) {
+					t.Errorf("attempt %d after %v, want between %v and %v", i, gap, tt.minGap, tt.maxGap)
+				}
+				gaps[gap.Round(10*time.Millisecond)] = true
+			}
+			if tt.wantJitter && len(gaps) < 2 {
+				t.Errorf("all attempts after the same delay %v", gaps)
+			}
+		})
+	}
+}
@@ -1,132 +1,177 @@
 package botan
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"io/ioutil"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/isvinogradov/botan/entities"
 )
 
-// Converts arbitrary data type to target struct
-func toStruct(data interface{}, target interface{}) error {
-	payload, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-	return json.Unmarshal(payload, &target)
-}
-
-// close HTTP response body
-func closeBody(r *http.Response) {
-	errRespBodyClose := r.Body.Close()
-	if errRespBodyClose != nil {
-		panic(errRespBodyClose)
+// close HTTP response body; the response is already processed at this point, so close errors only get logged
+func (rg *requestGate) closeBody(r *http.Response) {
+	if errRespBodyClose := r.Body.Close(); errRespBodyClose != nil {
+		rg.log.warn("failed to close response body", "error", errRespBodyClose)
 	}
 }
 
 type requestGate struct {
-	postTimeoutSeconds     int
-	getTimeoutSeconds      int
-	socks5ConnectionString string
-	postClient             *http.Client
-	getClient              *http.Client
+	log                  *botLogger
+	postTimeoutSeconds   int
+	uploadTimeoutSeconds int
+	getTimeoutSeconds    int
+	proxyUrl             string
+	proxyFromEnvironment bool
+	apiBaseUrl           string
+	httpClient           *http.Client // base client provided in config; nil to build one
+	retry                retryPolicy
+	limiter              outgoingLimiter
+	postClient           *http.Client
+	getClient            *http.Client
+	uploadClient         *http.Client
 }
 
+// Builds HTTP clients for ordinary requests, long polling and file transfers. They share the transport, so they
+// share connections too; only timeouts differ.
 func (rg *requestGate) checkAndInit() error {
-	var transport http.Transport
-	if rg.socks5ConnectionString == "" {
-		transport = http.Transport{Proxy: nil} // no proxy
+	var baseClient http.Client
+	if rg.httpClient != nil {
+		// proxy is a property of the transport, which belongs to the provided client
+		if rg.proxyUrl != "" || rg.proxyFromEnvironment {
+			return errors.New("proxy settings can't be combined with HTTPClient; set proxy in its transport instead")
+		}
+		baseClient = *rg.httpClient
 	} else {
-		proxyUrl, err := url.Parse(rg.socks5ConnectionString)
-		if err != nil {
-			return errors.New("failed to parse SOCKS5 connection string")
+		proxy, errProxy := proxyFunc(rg.proxyUrl, rg.proxyFromEnvironment, rg.apiBaseUrl)
+		if errProxy != nil {
+			return errProxy
 		}
-		transport = http.Transport{Proxy: http.ProxyURL(proxyUrl)}
+		baseClient.Transport = &http.Transport{Proxy: proxy} // nil Proxy means direct connections
 	}
 
-	rg.postClient = &http.Client{Transport: &transport, Timeout: time.Duration(rg.postTimeoutSeconds) * time.Second}
-	rg.getClient = &http.Client{Transport: &transport, Timeout: time.Duration(rg.getTimeoutSeconds) * time.Second}
+	// timeouts of the provided client are overridden: getUpdates must be allowed to wait for the whole
+	// long polling timeout, while ordinary requests must not
+	withTimeout := func(seconds int) *http.Client {
+		client := baseClient
+		client.Timeout = time.Duration(seconds) * time.Second
+		return &client
+	}
+	rg.postClient = withTimeout(rg.postTimeoutSeconds)
+	rg.getClient = withTimeout(rg.getTimeoutSeconds)
+	rg.uploadClient = withTimeout(rg.uploadTimeoutSeconds)
 
 	return nil
 }
 
-// Marshals payload to JSON and sends it to Telegram API server. Unmarshals response to target data struct.
-func (rg *requestGate) makePostRequest(url string, payload interface{}, target interface{}) error {
-	// marshal payload to JSON
-	jsonPayload, marshalErr := json.Marshal(payload)
-	if marshalErr != nil {
-		fmt.Printf("failed to marshal POST JSON")
-		return marshalErr
+// Marshals payload to JSON (or to multipart/form-data if it contains files to upload) and sends it
+// to Telegram API server. Unmarshals response to target data struct.
+// Failed request is repeated according to retry policy until ctx is done.
+func (rg *requestGate) makePostRequest(ctx context.Context, url string, payload interface{}, target interface{}) error {
+	body, errBody := newRequestBody(payload)
+	if errBody != nil {
+		rg.log.error("failed to encode request", "method", methodFromUrl(url), "error", errBody)
+		return errBody
 	}
 
-	// make HTTP request
-	r, errMakePost := rg.postClient.Post(url, "application/json", bytes.NewBuffer(jsonPayload))
-	if errMakePost != nil {
-		return errMakePost
-	}
-	defer closeBody(r)
-	if r.StatusCode != http.StatusOK {
-		bodyBytes, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			panic(err)
+	for attempt := 0; ; attempt++ {
+		// every attempt is a message sent to chat, so it's subject to limits
+		if errLimit := rg.limiter.wait(ctx, url, payload); errLimit != nil {
+			return errLimit
+		}
+		started := time.Now()
+		errPost := rg.post(ctx, url, body, target)
+		rg.logRequest(url, payload, attempt, time.Since(started), errPost)
+		if errPost == nil || ctx.Err() != nil || !body.replayable {
+			return errPost
+		}
+		delay, retry := rg.retry.backoff(attempt, errPost)
+		if !retry {
+			return errPost
+		}
+		rg.log.info("retrying request", "method", methodFromUrl(url), "attempt", attempt+1, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return errPost
+		case <-time.After(delay):
 		}
-		fmt.Println(string(bodyBytes))
+	}
+}
+
+func (rg *requestGate) logRequest(url string, payload interface{}, attempt int, latency time.Duration, err error) {
+	fields := []interface{}{"method", methodFromUrl(url), "attempt", attempt, "latency", latency}
+	if chatKey, _, ok := chatIdFromPayload(payload); ok {
+		fields = append(fields, "chat_id", chatKey)
+	}
+	if err != nil {
+		rg.log.warn("request failed", append(fields, errorFields(err)...)...)
+		return
+	}
+	rg.log.debug("request completed", fields...)
+}
 
-		return errors.New("POST status code != 200")
+// makes a single HTTP request
+func (rg *requestGate) post(ctx context.Context, url string, body *requestBody, target interface{}) error {
+	bodyReader, contentType := body.open()
+	defer bodyReader.Close() // stops multipart writer if request failed before reading the whole body
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodPost, url, bodyReader)
+	if errReq != nil {
+		return errReq
 	}
+	req.Header.Set("Content-Type", contentType)
 
-	// Decode response to ApiResponse struct and verify it
+	client := rg.postClient
+	if body.jsonPayload == nil {
+		client = rg.uploadClient // uploads take much longer than ordinary requests
+	}
+	r, errMakePost := client.Do(req)
+	if errMakePost != nil {
+		return redactUrlError(errMakePost)
+	}
+	defer rg.closeBody(r)
+	return decodeApiResponse(url, r, target)
+}
+
+// Decodes API response and verifies it. Unmarshals result to target if provided.
+func decodeApiResponse(url string, r *http.Response, target interface{}) error {
 	var apiResponse entities.ApiResponse
 	if errDecode := json.NewDecoder(r.Body).Decode(&apiResponse); errDecode != nil {
+		if r.StatusCode != http.StatusOK {
+			// not an API response at all, e.g. error page of a proxy
+			return &APIError{Method: methodFromUrl(url), ErrorCode: r.StatusCode, Description: http.StatusText(r.StatusCode)}
+		}
 		return errDecode
 	}
-	if !apiResponse.OK {
-		return errors.New("ApiResponse.OK == false")
-	}
-
-	// unmarshal response to target
-	if target != nil { // target may be nil if bool is expected as a result
-		if errConv := toStruct(apiResponse.Result, &target); errConv != nil {
-			return errConv
+	if !apiResponse.OK || r.StatusCode != http.StatusOK {
+		if apiResponse.ErrorCode == 0 {
+			apiResponse.ErrorCode = r.StatusCode
 		}
+		return newAPIError(url, &apiResponse)
 	}
 
-	// OTHER POSSIBLE SOLUTIONS:
-	// 0. errMapstruct := mapstructure.Decode(apiResponse.Result, target)
-	// POSSIBLE PROBLEM
-	// https://stackoverflow.com/questions/51813411/golang-mapstructure-not-working-as-expected
-	// 1. resultMsg, ok := targetResponse.Result.(*Message) // doesn't work because map[string]interface{} != Message
-	// 2. toStruct(apiResponse.Result, target) // works, but that's a HACK
-	// 3. switch t := apiResponse.Result.(type) {  // doesn't work
-	//			case entities.Message:
-	//				target = t
-	//			case *entities.Message:
-	//				target = t
-	//			case map[string]interface{}:
-	//				// that's it :(
-	//			}
-	// 4. Multiple ApiResponse structs for different response options  // will probably work. ugly.
-	// 5. func (ar *ApiResponse) UnmarshalJSON(b []byte) error { ??
-
+	// result is kept raw by ApiResponse, so it's decoded straight into target
+	if target != nil && len(apiResponse.Result) != 0 { // target is nil when only errors are of interest
+		return json.Unmarshal(apiResponse.Result, target)
+	}
 	return nil
 }
 
-// for getUpdates
-func (rg *requestGate) makeGetRequest(url string, target interface{}) error {
-	r, err := rg.getClient.Get(url) // long polling
+// for getUpdates; ctx aborts long polling request
+func (rg *requestGate) makeGetRequest(ctx context.Context, url string, target interface{}) error {
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if errReq != nil {
+		return errReq
+	}
+	r, err := rg.getClient.Do(req) // long polling
 	if err != nil {
-		return err
+		return redactUrlError(err)
 	}
-	defer closeBody(r)
+	defer rg.closeBody(r)
 
 	if r.StatusCode != http.StatusOK {
-		return errors.New("> wrong getUpdates response status code")
+		// e.g. 409 Conflict if webhook is set
+		return decodeApiResponse(url, r, nil)
 	}
 	return json.NewDecoder(r.Body).Decode(target)
 }
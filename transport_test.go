@@ -0,0 +1,61 @@
+package botan
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+// Typical response to sendMessage: a text message with entities and an inline keyboard
+var sendMessageResponse = []byte(`{"ok":true,"result":{"message_id":123,` +
+	`"from":{"id":123456,"is_bot":true,"first_name":"Test Bot","username":"test_bot"},` +
+	`"chat":{"id":42,"first_name":"John","last_name":"Doe","username":"johndoe","type":"private"},` +
+	`"date":1571234567,"text":"Hello, John! Choose an option below or send /help for the list of commands.",` +
+	`"entities":[{"type":"bold","offset":7,"length":4},{"type":"bot_command","offset":53,"length":5}],` +
+	`"reply_markup":{"inline_keyboard":[[{"text":"Yes","callback_data":"vote:1"},{"text":"No","callback_data":"vote:0"}]]}}}`)
+
+func newApiResponse(body []byte) *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}
+}
+
+func TestDecodeApiResponse(t *testing.T) {
+	var msg entities.Message
+	if errDecode := decodeApiResponse(MethodSendMessage, newApiResponse(sendMessageResponse), &msg); errDecode != nil {
+		t.Fatal(errDecode)
+	}
+	if msg.MessageId != 123 || msg.Chat.Id != 42 || len(msg.Entities) != 2 || msg.ReplyMarkup == nil {
+		t.Errorf("decoded %+v", msg)
+	}
+
+	var ok bool
+	if errDecode := decodeApiResponse(MethodDeleteMessage, newApiResponse([]byte(`{"ok":true,"result":true}`)), &ok); errDecode != nil || !ok {
+		t.Errorf("decoded %v, %v", ok, errDecode)
+	}
+}
+
+// Whole request path of a method: encoding, HTTP round trip to a local server and decoding of the result
+func BenchmarkSendMessage(b *testing.B) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(sendMessageResponse)
+	}))
+	defer api.Close()
+	bot, errBot := NewBot(&Config{Token: "1:test", APIBaseURL: api.URL}, &BotCallbacksContainer{})
+	if errBot != nil {
+		b.Fatal(errBot)
+	}
+	req := &SendMessageRequest{ChatId: 42, Text: "Hello, John!"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, errSend := bot.SendMessage(req); errSend != nil {
+			b.Fatal(errSend)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package botan
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/isvinogradov/botan/entities"
+)
+
+const (
+	webhookSecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	webhookMaxBodyBytes      = 1 << 20 // updates are far smaller; protects handler from oversized bodies
+)
+
+// http.Handler which receives updates pushed by Telegram after SetWebhook call
+// and routes them to the same callbacks as GetUpdates loop does.
+type webhookHandler struct {
+	bot *Bot
+}
+
+// Returns http.Handler to be mounted on the webhook URL. Use it instead of GetUpdates loop:
+// Telegram doesn't allow getUpdates calls while a webhook is set.
+func (bot *Bot) WebhookHandler() http.Handler {
+	return &webhookHandler{bot: bot}
+}
+
+func (wh *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// reject requests which don't come from Telegram
+	if secretPath := wh.bot.config.WebhookSecretPath; secretPath != "" {
+		// secret is the last segment of the path as is; "/secret/" or "/secret/.." don't match
+		lastSegment := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if subtle.ConstantTimeCompare([]byte(lastSegment), []byte(secretPath)) != 1 {
+			http.NotFound(w, r)
+			return
+		}
+	}
+	if secretToken := wh.bot.config.WebhookSecretToken; secretToken != "" {
+		gotToken := r.Header.Get(webhookSecretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(gotToken), []byte(secretToken)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var update entities.Update
+	if errDecode := json.NewDecoder(http.MaxBytesReader(w, r.Body, webhookMaxBodyBytes)).Decode(&update); errDecode != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	// callback errors are passed to OnError; Telegram must not redeliver the update because of them
+	wh.bot.processUpdate(&update)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,116 @@
+package botan_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/isvinogradov/botan"
+	"github.com/isvinogradov/botan/botantest"
+	"github.com/isvinogradov/botan/entities"
+)
+
+func TestWebhookSkipsUpdatesWithoutCallback(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+
+	var received []string
+	var errs []error
+	bot := srv.Bot(t, &botan.BotCallbacksContainer{
+		OnMessage: func(bot *botan.Bot, msg *entities.Message) error {
+			received = append(received, msg.Text)
+			return nil
+		},
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+	handler := bot.WebhookHandler()
+
+	updates := []string{
+		`{"update_id":1,"poll":{"id":"p","question":"?","options":[]}}`,
+		`{"update_id":2,"edited_message":{"message_id":1,"date":0,"chat":{"id":42,"type":"private"},"text":"edited"}}`,
+		`{"update_id":3,"callback_query":{"id":"q","from":{"id":42,"first_name":"u"},"chat_instance":"c","data":"d"}}`,
+		`{"update_id":4,"message":{"message_id":2,"date":0,"chat":{"id":42,"type":"private"},"text":"hello"}}`,
+	}
+	for _, body := range updates {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("update %s: status %d", body, rec.Code)
+		}
+	}
+
+	if len(errs) != 0 {
+		t.Errorf("OnError called for updates without callback: %v", errs)
+	}
+	if len(received) != 1 || received[0] != "hello" {
+		t.Errorf("OnMessage got %q, want [hello]", received)
+	}
+}
+
+func TestWebhookSecretPath(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+	conf := srv.Config()
+	conf.WebhookSecretPath = "s3cret"
+	bot, errBot := botan.NewBot(conf, &botan.BotCallbacksContainer{})
+	if errBot != nil {
+		t.Fatal(errBot)
+	}
+	handler := bot.WebhookHandler()
+
+	for target, wantCode := range map[string]int{
+		"/s3cret":             http.StatusOK,
+		"/hooks/s3cret":       http.StatusOK,
+		"/hooks/s3cret/":      http.StatusNotFound,
+		"/s3cret/..":          http.StatusNotFound,
+		"/hooks/s3cre":        http.StatusNotFound,
+		"/hooks/s3cret/other": http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"update_id":1}`)))
+		if rec.Code != wantCode {
+			t.Errorf("%s: status %d, want %d", target, rec.Code, wantCode)
+		}
+	}
+
+	conf = srv.Config()
+	conf.WebhookSecretPath = "hooks/s3cret"
+	if _, errBot := botan.NewBot(conf, &botan.BotCallbacksContainer{}); errBot == nil {
+		t.Error("secret path with slash accepted")
+	}
+}
+
+func TestSetWebhookDefaults(t *testing.T) {
+	srv := botantest.NewServer()
+	defer srv.Close()
+	conf := srv.Config()
+	conf.WebhookSecretToken = "token"
+	bot, errBot := botan.NewBot(conf, &botan.BotCallbacksContainer{
+		OnMessage: func(bot *botan.Bot, msg *entities.Message) error { return nil },
+	})
+	if errBot != nil {
+		t.Fatal(errBot)
+	}
+	srv.Respond(botan.MethodSetWebhook, botantest.OK(true), botantest.OK(true))
+
+	req := &botan.SetWebhookRequest{Url: "https://example.com/hook"}
+	if _, errSet := bot.SetWebhook(req); errSet != nil {
+		t.Fatal(errSet)
+	}
+	if req.AllowedUpdates != nil || req.SecretToken != "" {
+		t.Errorf("request of the caller was modified: %+v", req)
+	}
+	call, _ := srv.LastCall(botan.MethodSetWebhook)
+	if call.String("secret_token") != "token" || !strings.Contains(fmt.Sprint(call.Params["allowed_updates"]), "message") {
+		t.Errorf("sent %v", call.Params)
+	}
+
+	if _, errSet := bot.SetWebhook(nil); errSet != nil {
+		t.Fatal(errSet)
+	}
+	if call, _ := srv.LastCall(botan.MethodSetWebhook); call.String("secret_token") != "token" {
+		t.Errorf("sent %v", call.Params)
+	}
+}